@@ -0,0 +1,194 @@
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedArchive is returned if the archive is neither a zip nor a tar
+// archive.
+var ErrUnsupportedArchive = errors.New("unsupported archive format; upload a zip or a tar archive")
+
+// ErrArchiveTooLarge is returned if the extracted files of the archive exceed
+// the size limit.
+var ErrArchiveTooLarge = errors.New("extracted archive is too large")
+
+var zipMagic = []byte("PK\x03\x04")
+
+// Extract extracts the zip or the tar, optionally gzipped, archive to the dir
+// directory. The archive type is detected by its content.
+func Extract(archive string, dir string) error {
+	return ExtractLimited(archive, dir, 0)
+}
+
+// ExtractLimited works like Extract but returns ErrArchiveTooLarge as soon as
+// the extracted files exceed maxSize bytes in total; the size is unlimited if
+// maxSize is not positive. The files extracted so far are left in the dir.
+func ExtractLimited(archive string, dir string, maxSize int64) error {
+	remaining := int64(math.MaxInt64)
+	if maxSize > 0 {
+		remaining = maxSize
+	}
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	header := make([]byte, 512)
+	n, err := io.ReadFull(f, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("cannot read the archive: %w", err)
+	}
+	header = header[:n]
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if bytes.HasPrefix(header, zipMagic) {
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		return extractZip(f, info.Size(), dir, &remaining)
+	}
+	var r io.Reader = bufio.NewReader(f)
+	if bytes.HasPrefix(header, gzipMagic) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return extractTar(r, dir, &remaining)
+}
+
+func extractZip(r io.ReaderAt, size int64, dir string, remaining *int64) error {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedArchive, err)
+	}
+	for _, file := range z.File {
+		target, err := targetPath(dir, file.Name)
+		if err != nil {
+			return err
+		}
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(target, rc, remaining)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTar(r io.Reader, dir string, remaining *int64) error {
+	t := tar.NewReader(r)
+	for i := 0; ; i++ {
+		header, err := t.Next()
+		if err == io.EOF {
+			if i == 0 {
+				return ErrUnsupportedArchive
+			}
+			return nil
+		}
+		if err != nil {
+			if i == 0 {
+				return fmt.Errorf("%w: %v", ErrUnsupportedArchive, err)
+			}
+			return err
+		}
+		target, err := targetPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, t, remaining); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// targetPath returns the path of the archive entry in the dir directory. It
+// returns an error if the entry is outside of the directory.
+func targetPath(dir string, name string) (string, error) {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if rel, err := filepath.Rel(dir, target); err != nil || rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry %v is outside of the archive", name)
+	}
+	return target, nil
+}
+
+// writeFile writes the content of r to the file and subtracts its size from
+// remaining. It returns ErrArchiveTooLarge if the content is larger than
+// remaining.
+func writeFile(path string, r io.Reader, remaining *int64) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	limit := *remaining
+	if limit < math.MaxInt64 {
+		limit++
+	}
+	n, err := io.Copy(f, io.LimitReader(r, limit))
+	*remaining -= n
+	if err == nil && *remaining < 0 {
+		err = ErrArchiveTooLarge
+	}
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// OpenExtracted opens the bundle extracted to the dir directory or, if the
+// archive wraps the bundle in a directory, in its only subdirectory.
+func OpenExtracted(dir string) (Bundle, error) {
+	b, err := New(dir)
+	if err == nil {
+		return b, nil
+	}
+	infos, e := ioutil.ReadDir(dir)
+	if e != nil {
+		return b, e
+	}
+	if len(infos) == 1 && infos[0].IsDir() {
+		return OpenExtracted(filepath.Join(dir, infos[0].Name()))
+	}
+	return b, err
+}
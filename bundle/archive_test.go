@@ -0,0 +1,66 @@
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestTargetPath(t *testing.T) {
+	if _, err := targetPath("/data", "../etc/passwd"); err == nil {
+		t.Error("Expected an error for an entry outside of the archive")
+	}
+	if p, err := targetPath("/data", "bundle/10.0.0.1_master/file"); err != nil || p != "/data/bundle/10.0.0.1_master/file" {
+		t.Errorf("Expected /data/bundle/10.0.0.1_master/file, observed %v, %v", p, err)
+	}
+}
+
+func TestExtractLimited(t *testing.T) {
+	content := bytes.Repeat([]byte{0}, 1<<20)
+	var zipped bytes.Buffer
+	z := zip.NewWriter(&zipped)
+	w, err := z.Create("bundle/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"bundle/file1", "bundle/file2"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content) / 2)}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(content[:len(content)/2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{"zip": zipped.Bytes(), "tar.gz": gzipped.Bytes()} {
+		archive := filepath.Join(t.TempDir(), "bundle."+name)
+		if err := ioutil.WriteFile(archive, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := ExtractLimited(archive, t.TempDir(), int64(len(content))-1); !errors.Is(err, ErrArchiveTooLarge) {
+			t.Errorf("%v: expected %v, observed %v", name, ErrArchiveTooLarge, err)
+		}
+		if err := ExtractLimited(archive, t.TempDir(), int64(len(content))); err != nil {
+			t.Errorf("%v: unexpected error: %v", name, err)
+		}
+	}
+}
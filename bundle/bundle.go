@@ -6,27 +6,52 @@ import (
 	"io/ioutil"
 	"log"
 	"path/filepath"
-	"regexp"
+	"strings"
 )
 
-const hostRegexp = `^((([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.){3}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5]))_(agent_public|agent|master)$`
-
 // Host represents a host in a DC/OS cluster.
 type Host struct {
 	IP IP
 	Directory
 }
 
-type IP string
-
-// Bundle describes DC/OS diagnostics bundle.
+// Bundle describes a diagnostics bundle.
 type Bundle struct {
-	Hosts []Host
+	// Flavor is detected when the bundle is opened; see Flavor.
+	Flavor Flavor
+	Hosts  []Host
 	Directory
 }
 
-// New creates new Bundle
-func New(path string) (Bundle, error) {
+// New creates new Bundle. If several paths are given, the bundles are merged
+// into one: directories of the hosts with the same IP and type are merged, and
+// root-level files are taken from the first bundle which has them. A path
+// may also point to a single host directory, e.g. /uploads/10.0.0.1_master.
+// The bundle flavor is detected by the layout; bundles of different flavors
+// cannot be merged.
+func New(paths ...string) (Bundle, error) {
+	if len(paths) == 0 {
+		return Bundle{}, errors.New("bundle path is not specified")
+	}
+	b, err := open(paths[0])
+	if err != nil {
+		return b, err
+	}
+	for _, p := range paths[1:] {
+		other, err := open(p)
+		if err != nil {
+			return b, err
+		}
+		if other.Flavor != b.Flavor {
+			return b, fmt.Errorf("cannot merge the %v bundle %v with a %v bundle", other.Flavor, p, b.Flavor)
+		}
+		b.merge(other)
+	}
+	return b, nil
+}
+
+// open creates a Bundle from a single bundle directory or a host directory.
+func open(path string) (Bundle, error) {
 	b := Bundle{}
 	var err error
 	b.Type = DTRoot
@@ -35,45 +60,49 @@ func New(path string) (Bundle, error) {
 		log.Printf("bun.New: cannot determine absolute path: %v", err)
 		return b, err
 	}
-	infos, err := ioutil.ReadDir(b.Path)
+	if ip, dirType, ok := ParseHostDir(filepath.Base(b.Path)); ok {
+		if _, err := ioutil.ReadDir(b.Path); err != nil {
+			return b, err
+		}
+		host := Host{IP: ip, Directory: Directory{Type: dirType, Path: b.Path}}
+		b.Path = filepath.Dir(b.Path)
+		b.Hosts = []Host{host}
+		b.Flavor = FDCOS
+		return b, nil
+	}
+	b.Flavor, b.Hosts, err = detectFlavor(b.Directory)
 	if err != nil {
 		return b, err
 	}
-	re := regexp.MustCompile(hostRegexp)
-	for _, info := range infos {
-		if !info.IsDir() {
-			continue
-		}
-		groups := re.FindStringSubmatch(info.Name())
-		if groups == nil {
-			continue
-		}
-		var host Host
-		host.IP = IP(groups[1])
-		host.Path = filepath.Join(b.Path, info.Name())
-		switch groups[5] {
-		case "master":
-			host.Type = DTMaster
-		case "agent":
-			host.Type = DTAgent
-		case "agent_public":
-			host.Type = DTPublicAgent
-		default:
-			panic(fmt.Sprintf("Unknown directory type: %v", groups[5]))
-		}
-		b.Hosts = append(b.Hosts, host)
-	}
+	return b, nil
+}
 
-	if len(b.Hosts) == 0 {
-		_, err = b.OpenFile("summary-report")
-		if err != nil {
-			_, err = b.OpenFile("summary-errors-report")
-			if err != nil {
-				return b, errors.New("bundle not found in the given directory")
+// merge adds the hosts and the root directory of the other bundle to this one.
+// The directories of this bundle take precedence.
+func (b *Bundle) merge(other Bundle) {
+	b.Directory = b.Directory.merge(other.Directory)
+	for _, h := range other.Hosts {
+		merged := false
+		for i := range b.Hosts {
+			if b.Hosts[i].IP == h.IP && b.Hosts[i].Type == h.Type {
+				b.Hosts[i].Directory = b.Hosts[i].Directory.merge(h.Directory)
+				merged = true
+				break
 			}
 		}
+		if !merged {
+			b.Hosts = append(b.Hosts, h)
+		}
 	}
-	return b, nil
+}
+
+// Matches returns true if the selector is the IP address, the hostname, or
+// the type of the host. Types may be written with dashes, e.g. public-agent.
+func (h Host) Matches(selector string) bool {
+	dirType := strings.ReplaceAll(strings.ToLower(selector), "-", " ")
+	ip := ParseIP(selector)
+	return dirType == string(h.Type) || ip.Address != "" && h.IP.Address == ip.Address ||
+		h.IP.Hostname != "" && strings.EqualFold(h.IP.Hostname, selector)
 }
 
 func (b Bundle) Masters() []Host {
@@ -88,6 +117,16 @@ func (b Bundle) PublicAgents() []Host {
 	return b.filter(DTPublicAgent)
 }
 
+// ControlPlanes returns the control plane nodes of a Kubernetes bundle.
+func (b Bundle) ControlPlanes() []Host {
+	return b.filter(DTControlPlane)
+}
+
+// Workers returns the worker nodes of a Kubernetes bundle.
+func (b Bundle) Workers() []Host {
+	return b.filter(DTWorker)
+}
+
 func (b Bundle) filter(t DirType) (hosts []Host) {
 	hosts = make([]Host, 0, len(b.Hosts))
 	for _, host := range b.Hosts {
@@ -99,43 +138,64 @@ func (b Bundle) filter(t DirType) (hosts []Host) {
 }
 
 // ForEachFile finds all the files of a given type and pass them one by one to the do function.
-// It stops if the do function returns true.
-func (b Bundle) ForEachFile(fileTypeName FileTypeName, do func(f File) (stop bool)) {
+// It stops if the do function returns true. It returns ErrUnknownFileType if the file type
+// is not registered.
+func (b Bundle) ForEachFile(fileTypeName FileTypeName, do func(f File) (stop bool)) error {
 	f := func(d Directory) bool {
 		if f, err := d.OpenFile(fileTypeName); err == nil {
 			return do(f)
 		}
 		return false
 	}
-	b.ForEachDirectory(fileTypeName, f)
+	return b.ForEachDirectory(fileTypeName, f)
 }
 
 // ForEachDirectory finds all the bundle directories which contain a given type and pass them one by one to the do function.
-// It stops if the do function returns true.
-func (b Bundle) ForEachDirectory(fileTypeName FileTypeName, do func(d Directory) (stop bool)) {
-	if t := GetFileType(fileTypeName); t.ExistsOn(b.Type) {
+// It stops if the do function returns true. It returns ErrUnknownFileType if the file type
+// is not registered.
+func (b Bundle) ForEachDirectory(fileTypeName FileTypeName, do func(d Directory) (stop bool)) error {
+	t, err := GetFileType(fileTypeName)
+	if err != nil {
+		return err
+	}
+	if t.ExistsOn(b.Type) {
 		if do(b.Directory) {
-			return
+			return nil
 		}
 	}
 	for _, host := range b.Hosts {
-		if t := GetFileType(fileTypeName); t.ExistsOn(host.Type) {
+		if t.ExistsOn(host.Type) {
 			if do(host.Directory) {
-				return
+				return nil
 			}
 		}
 	}
+	return nil
+}
+
+// HasFiles returns true if any directory of the bundle contains a file of the
+// given type. It returns ErrUnknownFileType if the file type is not registered.
+func (b Bundle) HasFiles(fileTypeName FileTypeName) (bool, error) {
+	found := false
+	err := b.ForEachDirectory(fileTypeName, func(d Directory) bool {
+		_, err := d.FilePaths(fileTypeName)
+		found = err == nil
+		return found
+	})
+	return found, err
 }
 
 // ReadAnyJSON reads JSON files of a given file in each directory. The function returns nil after the first successful
 // read or the last read error if it couldn't ready any files.
 func (b Bundle) ReadAnyJSON(typeName FileTypeName, v interface{}) error {
 	var err error
-	b.ForEachDirectory(typeName, func(d Directory) (stop bool) {
+	if e := b.ForEachDirectory(typeName, func(d Directory) (stop bool) {
 		if err = d.ReadJSON(typeName, v); err != nil {
 			return false
 		}
 		return true
-	})
+	}); e != nil {
+		return e
+	}
 	return err
 }
@@ -0,0 +1,98 @@
+package bundle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewMerge(t *testing.T) {
+	b, err := New("test_bundles/merge/a", "test_bundles/merge/b", "test_bundles/ok/10.0.0.2_agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Masters()) != 1 || len(b.Agents()) != 2 {
+		t.Fatalf("Expected 1 master and 2 agents, observed %v and %v", len(b.Masters()), len(b.Agents()))
+	}
+	master := b.Masters()[0]
+	if len(master.Paths()) != 2 {
+		t.Errorf("Expected the master directories to be merged, observed %v", master.Paths())
+	}
+	var version struct{ Version string }
+	if err := master.ReadJSON("dcos-version", &version); err != nil || version.Version != "2.1.0" {
+		t.Errorf("Expected version 2.1.0 from the first bundle, observed %q, %v", version.Version, err)
+	}
+	var state struct{ Hostname string }
+	if err := master.ReadJSON("mesos-master-state", &state); err != nil || state.Hostname != "10.0.0.1" {
+		t.Errorf("Expected the master state from the second bundle, observed %q, %v", state.Hostname, err)
+	}
+	agent := b.Agents()[0]
+	if len(agent.Paths()) != 2 {
+		t.Errorf("Expected the agent 10.0.0.2 directories to be merged, observed %v", agent.Paths())
+	}
+	f, err := b.OpenFile("summary-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	content, _ := ioutil.ReadAll(f)
+	if string(content) != "from a\n" {
+		t.Errorf("Expected the root file from the first bundle, observed %q", content)
+	}
+}
+
+func TestNewKubernetes(t *testing.T) {
+	b, err := New("test_bundles/kubernetes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Flavor != FKubernetes {
+		t.Errorf("Expected the kubernetes flavor, observed %q", b.Flavor)
+	}
+	if len(b.ControlPlanes()) != 1 || len(b.Workers()) != 1 {
+		t.Fatalf("Expected 1 control plane and 1 worker, observed %v", b.Hosts)
+	}
+	cp := b.ControlPlanes()[0]
+	if cp.IP.Address != "10.0.1.1" || cp.IP.Hostname != "cp-1" {
+		t.Errorf("Expected the control plane 10.0.1.1 named cp-1, observed %+v", cp.IP)
+	}
+	if !cp.Matches("control-plane") || !cp.Matches("10.0.1.1") || !cp.Matches("cp-1") {
+		t.Errorf("Expected the control plane to match its type, address, and name")
+	}
+	if _, err := cp.FilePaths("etcd"); err != nil {
+		t.Error(err)
+	}
+	if _, err := New("test_bundles/kubernetes", "test_bundles/ok"); err == nil {
+		t.Error("Expected an error merging bundles of different flavors")
+	}
+}
+
+func FuzzKubernetesHosts(f *testing.F) {
+	nodes, err := ioutil.ReadFile("test_bundles/kubernetes/kubernetes/nodes.yaml")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(string(nodes))
+	f.Add("items:\n- metadata: {name: cp-1}\n  status: {addresses: [{type: InternalIP}]}\n")
+	f.Fuzz(func(t *testing.T, nodes string) {
+		root := t.TempDir()
+		for _, dir := range []string{"kubernetes", "nodes/cp-1", "nodes/worker-1"} {
+			if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := ioutil.WriteFile(filepath.Join(root, "kubernetes", "nodes.yaml"), []byte(nodes), 0644); err != nil {
+			t.Fatal(err)
+		}
+		hosts, _, err := kubernetesHosts(Directory{Type: DTRoot, Path: root})
+		if err != nil {
+			return
+		}
+		for _, h := range hosts {
+			if h.IP.IsZero() {
+				t.Errorf("Host without an IP in %q: %+v", nodes, h)
+			}
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package bundletest
+
+import (
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+// RunCheck runs the registered check with the name against the bundle the
+// way the runner does, see checks.Check.SafeRun. It fails the test if the
+// check is not registered.
+func RunCheck(t testing.TB, name string, b bundle.Bundle) checks.Results {
+	t.Helper()
+	c, err := checks.GetCheck(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.SafeRun(b)
+}
+
+// AssertStatus fails the test if the aggregated status of the results is not
+// the expected one.
+func AssertStatus(t testing.TB, results checks.Results, expected checks.Status) {
+	t.Helper()
+	if s := results.Status(); s != expected {
+		t.Errorf("Expected status %v, observed %v; results: %v", expected, s, results)
+	}
+}
+
+// AssertCounts fails the test if the numbers of the OK, PROBLEM, and
+// UNDEFINED results are not the expected ones.
+func AssertCounts(t testing.TB, results checks.Results, oks int, problems int, undefined int) {
+	t.Helper()
+	if len(results.OKs()) != oks || len(results.Problems()) != problems || len(results.Undefined()) != undefined {
+		t.Errorf("Expected %v OK, %v PROBLEM, and %v UNDEFINED results, observed %v, %v, and %v; results: %v",
+			oks, problems, undefined, len(results.OKs()), len(results.Problems()), len(results.Undefined()), results)
+	}
+}
+
+// AssertHostStatus fails the test if there is no result with the expected
+// status for the host with the IP address or hostname.
+func AssertHostStatus(t testing.TB, results checks.Results, host string, expected checks.Status) {
+	t.Helper()
+	for _, r := range results {
+		if r.IsHostSet() && r.Host.Matches(host) && r.Status == expected {
+			return
+		}
+	}
+	t.Errorf("Expected a %v result for host %v, observed %v", expected, host, results)
+}
@@ -0,0 +1,245 @@
+// Package bundletest builds synthetic bundles for testing checks, so the
+// tests don't need hand-crafted bundle directories:
+//
+//	b := bundletest.New(t)
+//	b.Master("10.0.0.1").File("mesos-master-state", `{"hostname": "10.0.0.1"}`)
+//	b.Agent("10.0.0.2").GzipFile("mesos-agent-log", "I0101 ...\n")
+//	results := bundletest.RunCheck(t, "node-count", b.Build())
+//	bundletest.AssertStatus(t, results, checks.SProblem)
+//
+// The files are written to a temporary directory which is removed when the
+// test finishes.
+package bundletest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+// Builder builds a bundle. Add the hosts and their files, then call Build.
+type Builder struct {
+	t      testing.TB
+	dir    string
+	flavor bundle.Flavor
+	root   *HostBuilder
+	hosts  []*HostBuilder
+}
+
+// HostBuilder adds files to the bundle root or a host directory.
+type HostBuilder struct {
+	b    *Builder
+	host bundle.Host
+}
+
+// New returns a Builder of a DC/OS bundle.
+func New(t testing.TB) *Builder {
+	t.Helper()
+	b := &Builder{t: t, dir: t.TempDir(), flavor: bundle.FDCOS}
+	b.root = &HostBuilder{b: b, host: bundle.Host{
+		Directory: bundle.Directory{Type: bundle.DTRoot, Path: b.dir},
+	}}
+	return b
+}
+
+// NewKubernetes returns a Builder of a Kubernetes bundle.
+func NewKubernetes(t testing.TB) *Builder {
+	t.Helper()
+	b := New(t)
+	b.flavor = bundle.FKubernetes
+	return b
+}
+
+// Root returns the builder of the bundle root directory.
+func (b *Builder) Root() *HostBuilder {
+	return b.root
+}
+
+// Master adds a DC/OS master with the IP address or hostname.
+func (b *Builder) Master(ip string) *HostBuilder {
+	return b.addHost(bundle.ParseIP(ip), bundle.DTMaster)
+}
+
+// Agent adds a DC/OS private agent with the IP address or hostname.
+func (b *Builder) Agent(ip string) *HostBuilder {
+	return b.addHost(bundle.ParseIP(ip), bundle.DTAgent)
+}
+
+// PublicAgent adds a DC/OS public agent with the IP address or hostname.
+func (b *Builder) PublicAgent(ip string) *HostBuilder {
+	return b.addHost(bundle.ParseIP(ip), bundle.DTPublicAgent)
+}
+
+// ControlPlane adds a Kubernetes control plane node with the name and the IP
+// address.
+func (b *Builder) ControlPlane(name string, ip string) *HostBuilder {
+	return b.addHost(nodeIP(name, ip), bundle.DTControlPlane)
+}
+
+// Worker adds a Kubernetes worker node with the name and the IP address.
+func (b *Builder) Worker(name string, ip string) *HostBuilder {
+	return b.addHost(nodeIP(name, ip), bundle.DTWorker)
+}
+
+func nodeIP(name string, ip string) bundle.IP {
+	id := bundle.ParseIP(ip)
+	id.Hostname = strings.ToLower(name)
+	if id.Family == "" {
+		id.Family = bundle.AFHostname
+	}
+	return id
+}
+
+func (b *Builder) addHost(ip bundle.IP, dirType bundle.DirType) *HostBuilder {
+	b.t.Helper()
+	if ip.IsZero() {
+		b.t.Fatalf("bundletest: empty address of a %v", dirType)
+	}
+	path := filepath.Join(b.dir, filepath.FromSlash(bundle.HostDirName(ip, dirType)))
+	if err := os.MkdirAll(path, 0755); err != nil {
+		b.t.Fatal(err)
+	}
+	h := &HostBuilder{b: b, host: bundle.Host{IP: ip, Directory: bundle.Directory{Type: dirType, Path: path}}}
+	b.hosts = append(b.hosts, h)
+	return h
+}
+
+// Build opens the bundle the way Bun does. For a Kubernetes bundle it writes
+// the kubernetes-nodes file first, so the nodes get their roles and IP
+// addresses. It fails the test if the opened bundle has other hosts than the
+// added ones.
+func (b *Builder) Build() bundle.Bundle {
+	b.t.Helper()
+	if b.flavor == bundle.FKubernetes {
+		b.root.File("kubernetes-nodes", b.kubernetesNodes())
+	}
+	result, err := bundle.New(b.dir)
+	if err != nil {
+		b.t.Fatalf("bundletest: cannot open the bundle: %v", err)
+	}
+	if result.Flavor != b.flavor {
+		b.t.Fatalf("bundletest: expected a %v bundle, opened a %v one", b.flavor, result.Flavor)
+	}
+	type hostKey struct {
+		ip      bundle.IP
+		dirType bundle.DirType
+		path    string
+	}
+	added := make(map[hostKey]bool, len(b.hosts))
+	expected := make([]bundle.Host, 0, len(b.hosts))
+	for _, h := range b.hosts {
+		added[hostKey{h.host.IP, h.host.Type, h.host.Path}] = true
+		expected = append(expected, h.host)
+	}
+	same := len(result.Hosts) == len(b.hosts)
+	for _, h := range result.Hosts {
+		same = same && added[hostKey{h.IP, h.Type, h.Path}]
+	}
+	if !same {
+		b.t.Fatalf("bundletest: the opened bundle has the hosts %v, expected %v", result.Hosts, expected)
+	}
+	return result
+}
+
+// kubernetesNodes returns the `kubectl get nodes -o yaml` output with the
+// names, roles, and internal IP addresses of the added nodes.
+func (b *Builder) kubernetesNodes() string {
+	var nodes strings.Builder
+	nodes.WriteString("items:\n")
+	for _, h := range b.hosts {
+		ip := h.host.IP
+		fmt.Fprintf(&nodes, "- metadata:\n    name: %q\n", ip.Hostname)
+		if h.host.Type == bundle.DTControlPlane {
+			nodes.WriteString("    labels:\n      node-role.kubernetes.io/control-plane: \"\"\n")
+		}
+		if ip.Address != "" {
+			fmt.Fprintf(&nodes, "  status:\n    addresses:\n    - type: InternalIP\n      address: %q\n", ip.Address)
+		}
+	}
+	return nodes.String()
+}
+
+// Host returns the host; it's useful to compare it with the check results.
+func (h *HostBuilder) Host() bundle.Host {
+	return h.host
+}
+
+// File writes the content to the file of the type. It fails the test if the
+// file type is unknown or doesn't belong to the host type.
+func (h *HostBuilder) File(t bundle.FileTypeName, content string) *HostBuilder {
+	h.b.t.Helper()
+	h.write(t, "", []byte(content))
+	return h
+}
+
+// GzipFile works like File but writes the content gzipped to the file with
+// the .gz extension.
+func (h *HostBuilder) GzipFile(t bundle.FileTypeName, content string) *HostBuilder {
+	h.b.t.Helper()
+	h.write(t, ".gz", h.gzip(content))
+	return h
+}
+
+// TruncatedGzipFile works like GzipFile but cuts off the gzip trailer, so the
+// content is read completely but the file is reported as read partially.
+func (h *HostBuilder) TruncatedGzipFile(t bundle.FileTypeName, content string) *HostBuilder {
+	h.b.t.Helper()
+	data := h.gzip(content)
+	// The trailer is the CRC-32 and the size of the content, 4 bytes each.
+	h.write(t, ".gz", data[:len(data)-8])
+	return h
+}
+
+func (h *HostBuilder) gzip(content string) []byte {
+	h.b.t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(content)); err != nil {
+		h.b.t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		h.b.t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// JSONFile works like File but writes the value encoded as JSON.
+func (h *HostBuilder) JSONFile(t bundle.FileTypeName, v interface{}) *HostBuilder {
+	h.b.t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		h.b.t.Fatal(err)
+	}
+	h.write(t, "", data)
+	return h
+}
+
+func (h *HostBuilder) write(t bundle.FileTypeName, ext string, data []byte) {
+	h.b.t.Helper()
+	fileType, err := bundle.GetFileType(t)
+	if err != nil {
+		h.b.t.Fatalf("bundletest: %v", err)
+	}
+	if !fileType.ExistsOn(h.host.Type) {
+		h.b.t.Fatalf("bundletest: %v files cannot be found on %v hosts", t, h.host.Type)
+	}
+	rel, err := filePath(fileType)
+	if err != nil {
+		h.b.t.Fatalf("bundletest: %v", err)
+	}
+	path := filepath.Join(h.host.Path, filepath.FromSlash(rel)) + ext
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		h.b.t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		h.b.t.Fatal(err)
+	}
+}
@@ -0,0 +1,97 @@
+package bundletest
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func TestBuild(t *testing.T) {
+	b := New(t)
+	b.Root().File("summary-report", "report\n")
+	b.Master("10.0.0.1").JSONFile("mesos-master-state", map[string]string{"hostname": "10.0.0.1"})
+	b.Agent("10.0.0.2").GzipFile("mesos-agent-log", "line 1\nline 2\n")
+	b.PublicAgent("10.0.0.3")
+	built := b.Build()
+	if len(built.Masters()) != 1 || len(built.Agents()) != 1 || len(built.PublicAgents()) != 1 {
+		t.Fatalf("Expected 1 master, 1 agent, and 1 public agent, observed %v", built.Hosts)
+	}
+	var state struct{ Hostname string }
+	if err := built.Masters()[0].ReadJSON("mesos-master-state", &state); err != nil || state.Hostname != "10.0.0.1" {
+		t.Errorf("Expected the master state, observed %q, %v", state.Hostname, err)
+	}
+	f, err := built.Agents()[0].OpenFile("mesos-agent-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if data, _ := ioutil.ReadAll(f); string(data) != "line 1\nline 2\n" {
+		t.Errorf("Expected the decompressed agent log, observed %q", data)
+	}
+	opened, err := bundle.New(built.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened.Hosts) != 3 || opened.Flavor != bundle.FDCOS {
+		t.Errorf("Expected the built bundle to be opened as a DC/OS bundle with 3 hosts, observed %v", opened.Hosts)
+	}
+}
+
+func TestBuildKubernetes(t *testing.T) {
+	b := NewKubernetes(t)
+	b.ControlPlane("cp-1", "10.0.1.1").File("etcd", "elected leader\n")
+	b.Worker("worker-1", "10.0.1.2").File("kubelet", "PLEG is not healthy\n")
+	built := b.Build()
+	if len(built.ControlPlanes()) != 1 || len(built.Workers()) != 1 {
+		t.Fatalf("Expected 1 control plane and 1 worker, observed %v", built.Hosts)
+	}
+	if _, err := built.Workers()[0].FilePaths("kubelet"); err != nil {
+		t.Error(err)
+	}
+	opened, err := bundle.New(built.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened.Flavor != bundle.FKubernetes || len(opened.ControlPlanes()) != 1 || len(opened.Workers()) != 1 {
+		t.Fatalf("Expected the built bundle to be opened as a Kubernetes bundle with a control plane and a worker, "+
+			"observed %v", opened.Hosts)
+	}
+	cp := opened.ControlPlanes()[0].IP
+	if cp.Address != "10.0.1.1" || cp.Hostname != "cp-1" {
+		t.Errorf("Expected the control plane cp-1 with the IP address 10.0.1.1, observed %+v", cp)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	for pattern, expected := range map[string]string{
+		"var/log/mesos/mesos-agent.log*":   "var/log/mesos/mesos-agent.log",
+		"dcos-mesos-master.service.[0-9]*": "dcos-mesos-master.service.0",
+		"pod-logs/*/*/*.log":               "pod-logs/x/x/x.log",
+	} {
+		if p := expand(pattern); p != expected {
+			t.Errorf("Expected %v for %v, observed %v", expected, pattern, p)
+		}
+	}
+}
+
+func TestAssertHostStatus(t *testing.T) {
+	b := New(t)
+	master := b.Master("10.0.0.1").Host()
+	results := checks.Results{{Status: checks.SProblem, Host: master}, {Status: checks.SOK}}
+	AssertHostStatus(t, results, "10.0.0.1", checks.SProblem)
+	AssertCounts(t, results, 1, 1, 0)
+	AssertStatus(t, results, checks.SProblem)
+}
+
+func TestTruncatedGzipFile(t *testing.T) {
+	b := New(t)
+	b.Master("10.0.0.1").TruncatedGzipFile("mesos-master-state", `{"hostname": "10.0.0.1"}`)
+	var state struct{ Hostname string }
+	err := b.Build().Masters()[0].ReadJSON("mesos-master-state", &state)
+	if !errors.Is(err, bundle.ErrPartialFile) || state.Hostname != "10.0.0.1" {
+		t.Errorf("Expected the state and %v, observed %q, %v", bundle.ErrPartialFile, state.Hostname, err)
+	}
+}
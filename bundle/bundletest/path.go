@@ -0,0 +1,57 @@
+package bundletest
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+// filePath returns the path of the file of the type relative to the host
+// directory: the first path which is not a glob pattern or, if all of them
+// are, a path which matches the first pattern.
+func filePath(t bundle.FileType) (string, error) {
+	if len(t.Paths) == 0 {
+		return "", fmt.Errorf("file type %v has no paths", t.Name)
+	}
+	for _, p := range t.Paths {
+		if !strings.ContainsAny(p, "*?[") {
+			return p, nil
+		}
+	}
+	pattern := t.Paths[0]
+	p := expand(pattern)
+	if ok, err := path.Match(pattern, p); err != nil || !ok {
+		return "", fmt.Errorf("cannot create a path matching %v of file type %v", pattern, t.Name)
+	}
+	return p, nil
+}
+
+// expand replaces the wildcards of the glob pattern: a trailing * with
+// nothing, other *s and ?s with "x", and character classes with their first
+// character.
+func expand(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) {
+				b.WriteByte('x')
+			}
+		case '?':
+			b.WriteByte('x')
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 2 {
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteByte(pattern[i+1])
+			i += end
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
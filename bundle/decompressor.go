@@ -0,0 +1,139 @@
+package bundle
+
+import (
+	"compress/bzip2"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/ulikunitz/xz"
+)
+
+// Decompressor opens a compressed bundle file. The returned File reads
+// decompressed data and closes the file when it is closed.
+type Decompressor func(file *os.File) (File, error)
+
+var (
+	decompressors   = make(map[string]Decompressor)
+	decompressorExt []string
+	decompressorsMu sync.RWMutex
+)
+
+// RegisterDecompressor adds the decompressor for the files with the given
+// extension, e.g. ".gz", to the decompressor registry. If a bundle file is not
+// found, OpenFile tries the extensions in the order they were registered.
+// It returns ErrDuplicateDecompressor if the extension is already registered.
+func RegisterDecompressor(ext string, d Decompressor) error {
+	decompressorsMu.Lock()
+	defer decompressorsMu.Unlock()
+	if _, dup := decompressors[ext]; dup {
+		return fmt.Errorf("%w: %v", ErrDuplicateDecompressor, ext)
+	}
+	decompressors[ext] = d
+	decompressorExt = append(decompressorExt, ext)
+	return nil
+}
+
+// CompressedExtensions returns the extensions of the registered decompressors
+// in the order they were registered.
+func CompressedExtensions() []string {
+	decompressorsMu.RLock()
+	defer decompressorsMu.RUnlock()
+	return append([]string(nil), decompressorExt...)
+}
+
+func getDecompressor(ext string) Decompressor {
+	decompressorsMu.RLock()
+	defer decompressorsMu.RUnlock()
+	return decompressors[ext]
+}
+
+// TrimCompressedExt removes the extension of a registered decompressor from
+// the file name, e.g. "dcos-marathon.service.gz" becomes "dcos-marathon.service".
+func TrimCompressedExt(name string) string {
+	ext := path.Ext(name)
+	if getDecompressor(ext) != nil {
+		return strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// Open opens the file at the given path. If the file has an extension of
+// a registered decompressor, the returned File reads decompressed data.
+func Open(filePath string) (File, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	d := getDecompressor(path.Ext(filePath))
+	if d == nil {
+		return file, nil
+	}
+	f, err := d(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// decompressedFile reads decompressed data from the reader and closes both
+// the reader, if it's an io.Closer, and the file.
+type decompressedFile struct {
+	io.Reader
+	file *os.File
+}
+
+func (d decompressedFile) Name() string {
+	return d.file.Name()
+}
+
+func (d decompressedFile) Close() error {
+	if c, ok := d.Reader.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			_ = d.file.Close()
+			return err
+		}
+	}
+	return d.file.Close()
+}
+
+func init() {
+	for _, d := range []struct {
+		ext string
+		d   Decompressor
+	}{
+		{".gz", func(file *os.File) (File, error) {
+			return newGzipFile(file)
+		}},
+		{".zst", func(file *os.File) (File, error) {
+			r, err := zstd.NewReader(file)
+			if err != nil {
+				return nil, err
+			}
+			return decompressedFile{r.IOReadCloser(), file}, nil
+		}},
+		{".xz", func(file *os.File) (File, error) {
+			r, err := xz.NewReader(file)
+			if err != nil {
+				return nil, err
+			}
+			return decompressedFile{r, file}, nil
+		}},
+		{".bz2", func(file *os.File) (File, error) {
+			return decompressedFile{bzip2.NewReader(file), file}, nil
+		}},
+		{".lz4", func(file *os.File) (File, error) {
+			return decompressedFile{lz4.NewReader(file), file}, nil
+		}},
+	} {
+		if err := RegisterDecompressor(d.ext, d.d); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -2,16 +2,13 @@ package bundle
 
 import (
 	"bufio"
-	"compress/gzip"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
-	"os"
-	"path"
-	"strings"
+
+	"gopkg.in/yaml.v2"
 )
 
 // DirType represent different types of the hosts.
@@ -26,32 +23,46 @@ const (
 	DTAgent = "agent"
 	// DTPublicAgent directory
 	DTPublicAgent = "public agent"
+	// DTControlPlane is a Kubernetes control plane node directory
+	DTControlPlane = "control plane"
+	// DTWorker is a Kubernetes worker node directory
+	DTWorker = "worker"
 )
 
+// Directory is a bundle root or host directory. A directory of a bundle
+// merged from several paths may consist of several physical directories;
+// files are searched in them in the order the bundles were merged.
 type Directory struct {
 	Type DirType
 	Path string
+	// merged are the physical directories merged into this one after the Path.
+	merged []string
+	// source provides the files of a virtual directory; see NewVirtualDirectory.
+	source Source
 }
 
-type bulkCloser []io.Closer
+// Paths returns the physical directories of the directory.
+func (d Directory) Paths() []string {
+	return append([]string{d.Path}, d.merged...)
+}
 
-func (bc bulkCloser) Close() error {
-	var e []string
-	for _, c := range bc {
-		if err := c.Close(); err != nil {
-			e = append(e, err.Error())
+// merge returns the directory which consists of the physical directories of
+// both directories; the directories of d take precedence. Duplicates are skipped.
+func (d Directory) merge(other Directory) Directory {
+	result := Directory{Type: d.Type, Path: d.Path, merged: append([]string(nil), d.merged...), source: d.source}
+	for _, p := range other.Paths() {
+		duplicate := false
+		for _, existing := range result.Paths() {
+			if existing == p {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result.merged = append(result.merged, p)
 		}
 	}
-	if len(e) > 0 {
-		return errors.New(strings.Join(e, "\n"))
-	}
-	return nil
-}
-
-type namer string
-
-func (name namer) Name() string {
-	return string(name)
+	return result
 }
 
 // File is a safe way to access bundle files.
@@ -61,60 +72,65 @@ type File interface {
 }
 
 // OpenFile opens the files of the typeName file type.
-// If the file is not found, it tries to open it from a correspondent .gzip archive.
-// If the .gzip archive is not found as well then returns an error.
+// If the file is not found, it tries to open it from a correspondent compressed
+// file, e.g. a .gz archive; see RegisterDecompressor.
+// If the compressed file is not found as well then returns an error wrapping
+// ErrFileNotFound.
+// If the file type has AllMatches set, the returned File reads all the matching
+// files from the oldest to the newest as one stream.
+// Truncated and corrupted archives are read as far as possible; use Partial to
+// find out if the whole file was read.
+// It returns ErrUnknownFileType if the file type is not registered and
+// ErrWrongDirType if the file type cannot be found in the directory of this type.
 // Caller is responsible for closing the file.
 func (d Directory) OpenFile(typeName FileTypeName) (File, error) {
-	fileType := GetFileType(typeName)
-	ok := false
-	for _, dirType := range fileType.DirTypes {
-		if dirType == d.Type {
-			ok = true
-			break
-		}
+	files, err := d.OpenFiles(typeName)
+	if err != nil {
+		return nil, err
 	}
-	if !ok {
-		panic(fmt.Sprintf("%v file type does not belong to %v hosts", fileType, d.Type))
+	if len(files) == 1 {
+		return files[0], nil
 	}
-	for _, localPath := range fileType.Paths {
-		filePath := path.Join(d.Path, localPath)
-		file, err := os.Open(filePath)
-		if err == nil {
-			return file, nil // found
-		}
-		if !os.IsNotExist(err) {
-			return nil, err // error
-		}
-		// not found
-		// try to open correspondent .gz file
-		filePath += ".gz"
-		file, err = os.Open(filePath)
+	return &multiFile{files: files}, nil
+}
+
+// OpenFiles opens the physical files of the typeName file type from the
+// oldest to the newest; see FilePaths. Caller is responsible for closing the files.
+func (d Directory) OpenFiles(typeName FileTypeName) ([]File, error) {
+	paths, err := d.FilePaths(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if d.source != nil {
+		f, err := d.source.Open(typeName)
 		if err != nil {
-			if !os.IsNotExist(err) {
-				return nil, err // error
-			}
-			continue // not found
+			return nil, err
 		}
-		// found
-		r, err := gzip.NewReader(file)
+		return []File{f}, nil
+	}
+	files := make([]File, 0, len(paths))
+	for _, p := range paths {
+		f, err := Open(p)
 		if err != nil {
-			return nil, err // error
+			_ = closeFiles(files)
+			return nil, err
 		}
-		return struct {
-			io.Reader
-			io.Closer
-			namer
-		}{io.Reader(r), bulkCloser{r, file}, namer(filePath)}, nil
+		files = append(files, f)
 	}
-	return nil, fmt.Errorf("file(s) not found: %v", strings.Join(fileType.Paths, ", "))
+	return files, nil
 }
 
 // ReadJSON reads JSON-encoded data from the bundle file and stores the result in
-// the value pointed to by v.
+// the value pointed to by v. It returns ErrNotJSON if the content type of the
+// file type is not JSON. If the file is read partially, it returns an error
+// wrapping ErrPartialFile; v holds the data which could be decoded.
 func (d Directory) ReadJSON(typeName FileTypeName, v interface{}) error {
-	fileType := GetFileType(typeName)
+	fileType, err := GetFileType(typeName)
+	if err != nil {
+		return err
+	}
 	if fileType.ContentType != CTJson {
-		panic(fmt.Sprintf("Content of the %v file is not JSON", typeName))
+		return fmt.Errorf("%w: %v", ErrNotJSON, typeName)
 	}
 	file, err := d.OpenFile(typeName)
 	if err != nil {
@@ -129,49 +145,108 @@ func (d Directory) ReadJSON(typeName FileTypeName, v interface{}) error {
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(data, v)
+	return partialDecode(file, json.Unmarshal(data, v))
 }
 
+// ReadYAML reads YAML-encoded data from the bundle file and stores the result
+// in the value pointed to by v. It returns ErrNotYAML if the content type of
+// the file type is not YAML. Partially read files are reported like in ReadJSON.
+func (d Directory) ReadYAML(typeName FileTypeName, v interface{}) error {
+	fileType, err := GetFileType(typeName)
+	if err != nil {
+		return err
+	}
+	if fileType.ContentType != CTYaml {
+		return fmt.Errorf("%w: %v", ErrNotYAML, typeName)
+	}
+	file, err := d.OpenFile(typeName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("bun.directory.ReadYAML: Cannot close file: %v", err)
+		}
+	}()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	return partialDecode(file, yaml.Unmarshal(data, v))
+}
+
+// partialDecode adds the partial read error of the file to the decoding error.
+func partialDecode(file File, err error) error {
+	partial := Partial(file)
+	if partial == nil {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, partial)
+	}
+	return partial
+}
+
+// ScanLines calls the function f for each line of the file of the given type.
+// n is the line number in the logical stream if the file type has AllMatches
+// set. It stops when f returns true. Lines keep their line endings, except
+// for the last line of a file which doesn't end with a newline.
 func (d Directory) ScanLines(t FileTypeName, f func(n int, line string) bool) (File, error) {
-	file, err := d.OpenFile(t)
+	i := 0
+	return d.ScanFiles(t, func(_ File, _ int, line string) bool {
+		i++
+		return f(i, line)
+	})
+}
+
+// ScanFiles works like ScanLines but also passes the physical file the line
+// belongs to, and n is the line number in the physical file. It returns the
+// logical file which is closed.
+func (d Directory) ScanFiles(t FileTypeName, f func(file File, n int, line string) bool) (File, error) {
+	files, err := d.OpenFiles(t)
 	if err != nil {
 		return nil, err
 	}
-	closeFunc := func() error {
+	var logical File = &multiFile{files: files}
+	if len(files) == 1 {
+		logical = files[0]
+	}
+	for i, file := range files {
+		stop, err := scanFile(file, f)
+		if err != nil || stop {
+			if e := closeFiles(files[i:]); e != nil {
+				return nil, fmt.Errorf("bun.bundle.ScanLines: Cannot close file %v with error: %v",
+					file.Name(), e)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return logical, nil
+		}
 		if err := file.Close(); err != nil {
-			e := fmt.Sprintf("bun.bundle.ScanLines: Cannot close file %v with error: %v",
+			_ = closeFiles(files[i+1:])
+			return nil, fmt.Errorf("bun.bundle.ScanLines: Cannot close file %v with error: %v",
 				file.Name(), err)
-			if strings.HasSuffix(file.Name(), ".gz") {
-				e += fmt.Sprintf("The .gz file might be corrupted. Try to fix it with"+
-					" the gzrecover command and run the check again:\n"+
-					"1) brew install gzrt\n"+
-					"2) gzrecover -o %v %v", strings.TrimSuffix(file.Name(), ".gz"),
-					file.Name())
-			}
-			return fmt.Errorf(e)
 		}
-		return nil
 	}
+	return logical, nil
+}
+
+func scanFile(file File, f func(file File, n int, line string) bool) (bool, error) {
 	reader := bufio.NewReader(file)
 	for i := 1; ; i++ {
 		line, err := reader.ReadString('\n')
-		if f(i, line) {
-			if err := closeFunc(); err != nil {
-				return nil, err
-			}
-			return file, nil
+		if err == io.EOF && line == "" {
+			return false, nil
+		}
+		if f(file, i, line) {
+			return true, nil
 		}
 		if err == io.EOF {
-			if err := closeFunc(); err != nil {
-				return nil, err
-			}
-			return file, nil
+			return false, nil
 		}
 		if err != nil {
-			if err := closeFunc(); err != nil {
-				return nil, err
-			}
-			return nil, err
+			return false, err
 		}
 	}
 }
@@ -0,0 +1,111 @@
+package bundle
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenFileErrors(t *testing.T) {
+	b, err := New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	master := b.Masters()[0]
+	if _, err := master.OpenFile("no-such-file-type"); !errors.Is(err, ErrUnknownFileType) {
+		t.Errorf("Expected %v, observed %v", ErrUnknownFileType, err)
+	}
+	if _, err := master.OpenFile("mesos-agent-state"); !errors.Is(err, ErrWrongDirType) {
+		t.Errorf("Expected %v, observed %v", ErrWrongDirType, err)
+	}
+	f, err := master.OpenFile("mesos-master-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	b, err := New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v interface{}
+	if err := b.Agents()[0].ReadJSON("cpuinfo", &v); !errors.Is(err, ErrNotJSON) {
+		t.Errorf("Expected %v, observed %v", ErrNotJSON, err)
+	}
+	if err := b.ReadAnyJSON("no-such-file-type", &v); !errors.Is(err, ErrUnknownFileType) {
+		t.Errorf("Expected %v, observed %v", ErrUnknownFileType, err)
+	}
+	var state struct{ Hostname string }
+	if err := b.ReadAnyJSON("mesos-master-state", &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Hostname != "10.0.0.1" {
+		t.Errorf("Expected hostname 10.0.0.1, observed %v", state.Hostname)
+	}
+}
+
+func TestReadYAMLPartial(t *testing.T) {
+	dir := t.TempDir()
+	data := gzipData(t, "items:\n- metadata:\n    name: cp-1\n")
+	path := filepath.Join(dir, "kubernetes", "nodes.yaml.gz")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Without the gzip trailer the content is read completely, but the file is partial.
+	if err := ioutil.WriteFile(path, data[:len(data)-8], 0644); err != nil {
+		t.Fatal(err)
+	}
+	var nodes kubernetesNodeList
+	err := Directory{Type: DTRoot, Path: dir}.ReadYAML("kubernetes-nodes", &nodes)
+	if !errors.Is(err, ErrPartialFile) {
+		t.Errorf("Expected %v, observed %v", ErrPartialFile, err)
+	}
+	if len(nodes.Items) != 1 || nodes.Items[0].Metadata.Name != "cp-1" {
+		t.Errorf("Expected the node cp-1 to be decoded, observed %+v", nodes)
+	}
+}
+
+func TestRegisterFileTypeErrors(t *testing.T) {
+	if err := RegisterFileType(FileType{Name: "cpuinfo"}); !errors.Is(err, ErrDuplicateFileType) {
+		t.Errorf("Expected %v, observed %v", ErrDuplicateFileType, err)
+	}
+	f := FileType{Name: "register-test", DirTypes: []DirType{DTMaster, DTMaster}}
+	if err := RegisterFileType(f); !errors.Is(err, ErrInvalidFileType) {
+		t.Errorf("Expected %v, observed %v", ErrInvalidFileType, err)
+	}
+}
+
+func TestOpenFileCompressed(t *testing.T) {
+	for _, ext := range []string{"zst", "xz", "bz2", "lz4"} {
+		d := Directory{Type: DTMaster, Path: "test_bundles/compressed/" + ext}
+		var lines []string
+		_, err := d.ScanLines("mesos-master-log", func(n int, line string) bool {
+			lines = append(lines, line)
+			return false
+		})
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", ext, err)
+			continue
+		}
+		if len(lines) != 2 || lines[1] != "second line\n" {
+			t.Errorf("%v: unexpected lines: %q", ext, lines)
+		}
+	}
+}
+
+func TestTrimCompressedExt(t *testing.T) {
+	for name, expected := range map[string]string{
+		"dcos-marathon.service.gz":  "dcos-marathon.service",
+		"dcos-marathon.service.zst": "dcos-marathon.service",
+		"dcos-marathon.service":     "dcos-marathon.service",
+		"5050-master_state.json":    "5050-master_state.json",
+	} {
+		if observed := TrimCompressedExt(name); observed != expected {
+			t.Errorf("TrimCompressedExt(%q): expected %q, observed %q", name, expected, observed)
+		}
+	}
+}
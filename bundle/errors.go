@@ -0,0 +1,27 @@
+package bundle
+
+import "errors"
+
+var (
+	// ErrUnknownFileType is returned when a file type is not in the registry.
+	ErrUnknownFileType = errors.New("unknown file type")
+	// ErrDuplicateFileType is returned when a file type is registered twice.
+	ErrDuplicateFileType = errors.New("duplicate file type")
+	// ErrInvalidFileType is returned when a file type description is not valid.
+	ErrInvalidFileType = errors.New("invalid file type")
+	// ErrWrongDirType is returned when a file of the given type cannot be found
+	// in a directory of the given type, e.g. Mesos master state on agents.
+	ErrWrongDirType = errors.New("file type does not belong to the directory type")
+	// ErrFileNotFound is returned when none of the file type paths exists.
+	ErrFileNotFound = errors.New("file(s) not found")
+	// ErrNotJSON is returned when a file is read as JSON but its content type is not JSON.
+	ErrNotJSON = errors.New("file content is not JSON")
+	// ErrNotYAML is returned when a file is read as YAML but its content type is not YAML.
+	ErrNotYAML = errors.New("file content is not YAML")
+	// ErrDuplicateDecompressor is returned when a decompressor for the same
+	// extension is registered twice.
+	ErrDuplicateDecompressor = errors.New("duplicate decompressor")
+	// ErrPartialFile is returned by Partial when only a part of the file could be read,
+	// e.g. a .gz archive is truncated.
+	ErrPartialFile = errors.New("file is read partially")
+)
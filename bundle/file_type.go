@@ -2,6 +2,7 @@ package bundle
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -11,6 +12,8 @@ type ContentType string
 const (
 	// CTJson represents CTJson files.
 	CTJson ContentType = "JSON"
+	// CTYaml represents YAML files, e.g. kubectl get -o yaml output.
+	CTYaml = "YAML"
 	// CTJournal represents CTJournal files.
 	CTJournal = "journal"
 	// CTDmesg represents dmesg files.
@@ -27,11 +30,18 @@ type FileTypeName string
 type FileType struct {
 	Name        FileTypeName `yaml:"name"`
 	ContentType ContentType  `yaml:"contentType"`
-	Paths       []string     `yaml:"paths"`
-	Description string       `yaml:"description"`
+	// Paths are alternative paths of the file relative to the host directory;
+	// they may be glob patterns, e.g. var/log/mesos/mesos-agent.log*.
+	Paths       []string `yaml:"paths"`
+	Description string   `yaml:"description"`
 	// DirTypes defines on which host types this file can be found.
 	// For example, dcos-marathon.service file can be found only on the masters.
 	DirTypes []DirType `yaml:"dirTypes"`
+	// AllMatches makes OpenFile read all the files matching the Paths, e.g.
+	// rotated logs, as one stream instead of the first one found.
+	AllMatches bool `yaml:"allMatches"`
+	// Flavor is the flavor of the bundles which contain the file type.
+	Flavor Flavor `yaml:"flavor,omitempty"`
 }
 
 func (t FileType) ExistsOn(dirType DirType) bool {
@@ -48,32 +58,47 @@ var (
 	fileTypesMu sync.RWMutex
 )
 
-// RegisterFileType adds the file type to the file type registry. It panics
-// if the file type with the same name is already registered.
-func RegisterFileType(f FileType) {
+// RegisterFileType adds the file type to the file type registry. It returns
+// ErrDuplicateFileType if the file type with the same name is already registered.
+func RegisterFileType(f FileType) error {
 	fileTypesMu.Lock()
 	defer fileTypesMu.Unlock()
 	if _, dup := fileTypes[f.Name]; dup {
-		panic(fmt.Sprintf("bun.RegisterFileType: called twice for file type %v", f.Name))
+		return fmt.Errorf("%w: %v", ErrDuplicateFileType, f.Name)
 	}
 	dirTypes := make(map[DirType]struct{})
 	for _, t := range f.DirTypes {
 		if _, ok := dirTypes[t]; ok {
-			panic(fmt.Sprintf("bun.RegisterFileType: duplicate DirType: %v in file type %v", t, f.Name))
+			return fmt.Errorf("%w: duplicate DirType %v in file type %v", ErrInvalidFileType, t, f.Name)
 		}
 		dirTypes[t] = struct{}{}
 	}
 	fileTypes[f.Name] = f
+	return nil
 }
 
-// GetFileType returns a file type by its name. It panics if the file type
-// is not in the registry.
-func GetFileType(typeName FileTypeName) FileType {
+// GetFileType returns a file type by its name. It returns ErrUnknownFileType
+// if the file type is not in the registry.
+func GetFileType(typeName FileTypeName) (FileType, error) {
 	fileTypesMu.RLock()
 	defer fileTypesMu.RUnlock()
 	fileType, ok := fileTypes[typeName]
 	if !ok {
-		panic(fmt.Sprintf("bun.RegisterFileType: No such fileType: %v", typeName))
+		return fileType, fmt.Errorf("%w: %v", ErrUnknownFileType, typeName)
 	}
-	return fileType
+	return fileType, nil
+}
+
+// FileTypes returns all the registered file types sorted by name.
+func FileTypes() []FileType {
+	fileTypesMu.RLock()
+	defer fileTypesMu.RUnlock()
+	types := make([]FileType, 0, len(fileTypes))
+	for _, t := range fileTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	return types
 }
@@ -0,0 +1,168 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// Flavor is a kind of diagnostics bundles with its own layout, host types,
+// and file types.
+type Flavor string
+
+const (
+	// FDCOS is a DC/OS diagnostics bundle with the host directories in the
+	// root, e.g. 10.0.0.1_master.
+	FDCOS Flavor = "dcos"
+	// FKubernetes is a DKP or Konvoy diagnostics bundle with the node
+	// directories in the nodes directory and kubectl dumps in the kubernetes
+	// directory.
+	FKubernetes Flavor = "kubernetes"
+)
+
+// kubernetesNodesDir is the directory of a Kubernetes bundle which contains
+// a directory per node.
+const kubernetesNodesDir = "nodes"
+
+// flavor describes how to find the hosts of a bundle of the flavor.
+type flavor struct {
+	name Flavor
+	// hosts returns the host directories of the bundle with the root
+	// directory; ok is false if the bundle is not of this flavor.
+	hosts func(root Directory) (hosts []Host, ok bool, err error)
+}
+
+// flavors are tried in this order when a bundle is opened.
+var flavors = []flavor{
+	{FDCOS, dcosHosts},
+	{FKubernetes, kubernetesHosts},
+}
+
+// detectFlavor returns the flavor and the hosts of the bundle with the root
+// directory.
+func detectFlavor(root Directory) (Flavor, []Host, error) {
+	for _, f := range flavors {
+		hosts, ok, err := f.hosts(root)
+		if err != nil {
+			return "", nil, err
+		}
+		if ok {
+			return f.name, hosts, nil
+		}
+	}
+	return "", nil, fmt.Errorf("bundle not found in the given directory %v", root.Path)
+}
+
+// dcosHosts finds the host directories in the root directory. A bundle
+// without host directories is a DC/OS bundle if it has a summary report.
+func dcosHosts(root Directory) ([]Host, bool, error) {
+	infos, err := ioutil.ReadDir(root.Path)
+	if err != nil {
+		return nil, false, err
+	}
+	var hosts []Host
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		ip, dirType, ok := ParseHostDir(info.Name())
+		if !ok {
+			continue
+		}
+		var host Host
+		host.IP = ip
+		host.Type = dirType
+		host.Path = filepath.Join(root.Path, info.Name())
+		hosts = append(hosts, host)
+	}
+	if len(hosts) > 0 {
+		return hosts, true, nil
+	}
+	for _, t := range []FileTypeName{"summary-report", "summary-errors-report"} {
+		if _, err := root.FilePaths(t); err == nil {
+			return nil, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// kubernetesNodeList is the part of the `kubectl get nodes -o yaml` output
+// needed to find out the node roles and addresses.
+type kubernetesNodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `yaml:"name"`
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Status struct {
+			Addresses []struct {
+				Type    string `yaml:"type"`
+				Address string `yaml:"address"`
+			} `yaml:"addresses"`
+		} `yaml:"status"`
+	} `yaml:"items"`
+}
+
+// kubernetesHosts finds the node directories in the nodes directory. The
+// node roles and internal IP addresses are taken from the kubernetes-nodes
+// file; nodes which are missing from it are workers. A bundle without node
+// directories is a Kubernetes bundle if it has the kubernetes-nodes file.
+func kubernetesHosts(root Directory) ([]Host, bool, error) {
+	var nodes kubernetesNodeList
+	err := root.ReadYAML("kubernetes-nodes", &nodes)
+	if errors.Is(err, ErrPartialFile) {
+		// Use the nodes which could be read.
+		err = nil
+	}
+	hasNodes := err == nil
+	if err != nil && !errors.Is(err, ErrFileNotFound) {
+		return nil, false, fmt.Errorf("cannot read the Kubernetes nodes: %w", err)
+	}
+	type node struct {
+		ip           IP
+		controlPlane bool
+	}
+	known := make(map[string]node)
+	for _, item := range nodes.Items {
+		n := node{ip: ParseIP(item.Metadata.Name)}
+		for _, a := range item.Status.Addresses {
+			if a.Type == "InternalIP" {
+				ip := ParseIP(a.Address)
+				ip.Hostname = n.ip.Hostname
+				n.ip = ip
+				break
+			}
+		}
+		_, n.controlPlane = item.Metadata.Labels["node-role.kubernetes.io/control-plane"]
+		if _, ok := item.Metadata.Labels["node-role.kubernetes.io/master"]; ok {
+			n.controlPlane = true
+		}
+		known[item.Metadata.Name] = n
+	}
+	infos, err := ioutil.ReadDir(filepath.Join(root.Path, kubernetesNodesDir))
+	if err != nil {
+		return nil, hasNodes, nil
+	}
+	var hosts []Host
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		n, ok := known[info.Name()]
+		if !ok {
+			n.ip = ParseIP(info.Name())
+			if n.ip.Family == AFHostname && !hostnameRegexp.MatchString(info.Name()) {
+				continue
+			}
+		}
+		host := Host{IP: n.ip}
+		host.Type = DTWorker
+		if n.controlPlane {
+			host.Type = DTControlPlane
+		}
+		host.Path = filepath.Join(root.Path, kubernetesNodesDir, info.Name())
+		hosts = append(hosts, host)
+	}
+	return hosts, hasNodes || len(hosts) > 0, nil
+}
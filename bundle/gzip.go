@@ -0,0 +1,194 @@
+package bundle
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// PartialFile is a File which might have been read only partially, e.g.
+// a truncated or corrupted .gz archive.
+type PartialFile interface {
+	File
+	// Partial returns an error wrapping ErrPartialFile if some data couldn't
+	// be read. It's meaningful only after the file has been read to the end.
+	Partial() error
+}
+
+// Partial returns an error wrapping ErrPartialFile if the file was read
+// only partially, and nil otherwise.
+func Partial(f File) error {
+	if p, ok := f.(PartialFile); ok {
+		return p.Partial()
+	}
+	return nil
+}
+
+// gzipMagic starts a gzip member header: ID1, ID2, and the deflate
+// compression method.
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// countingReader tracks the offset of the underlying file. It implements
+// the flate.Reader interface, so the gzip reader doesn't read ahead and the
+// offset is the position of the decompressor in the file.
+type countingReader struct {
+	r      *bufio.Reader
+	offset int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.offset += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.offset++
+	}
+	return b, err
+}
+
+// gzipFile decompresses a .gz file tolerating errors: it returns all the data
+// it can decompress from a truncated stream, and, if a gzip member is corrupted,
+// it skips to the next member of a multi-member archive.
+type gzipFile struct {
+	file        *os.File
+	name        string
+	cr          *countingReader
+	z           *gzip.Reader
+	memberStart int64
+	done        bool
+	// err is the first decompression error.
+	err error
+	// skipped is the number of gzip members which couldn't be read to the end.
+	skipped int
+}
+
+func newGzipFile(file *os.File) (*gzipFile, error) {
+	g := &gzipFile{file: file, name: file.Name()}
+	g.cr = &countingReader{r: bufio.NewReader(file)}
+	z, err := gzip.NewReader(g.cr)
+	if err != nil {
+		return nil, err
+	}
+	z.Multistream(false)
+	g.z = z
+	return g, nil
+}
+
+func (g *gzipFile) Name() string {
+	return g.name
+}
+
+func (g *gzipFile) Read(p []byte) (int, error) {
+	for !g.done {
+		n, err := g.z.Read(p)
+		switch {
+		case err == nil:
+			return n, nil
+		case err == io.EOF:
+			g.nextMember()
+		default:
+			if g.err == nil {
+				g.err = err
+			}
+			g.skipped++
+			// The decompressor doesn't read ahead, so the data before the
+			// offset has already been interpreted as a part of the member.
+			g.resync(g.cr.offset)
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+	return 0, io.EOF
+}
+
+// nextMember starts reading the next gzip member which follows the current one.
+func (g *gzipFile) nextMember() {
+	g.memberStart = g.cr.offset
+	err := g.z.Reset(g.cr)
+	switch {
+	case err == nil:
+		g.z.Multistream(false)
+	case err == io.EOF:
+		g.done = true
+	default:
+		// Garbage after the member.
+		if g.err == nil {
+			g.err = err
+		}
+		g.skipped++
+		g.resync(g.memberStart + 1)
+	}
+}
+
+// resync searches for the next valid gzip member header starting from the
+// offset. If there are no more members, it marks the file as read.
+func (g *gzipFile) resync(offset int64) {
+	for {
+		if _, err := g.file.Seek(offset, io.SeekStart); err != nil {
+			g.done = true
+			return
+		}
+		r := bufio.NewReader(g.file)
+		pos, found := findMagic(r, offset)
+		if !found {
+			g.done = true
+			return
+		}
+		if _, err := g.file.Seek(pos, io.SeekStart); err != nil {
+			g.done = true
+			return
+		}
+		g.cr = &countingReader{r: bufio.NewReader(g.file), offset: pos}
+		g.memberStart = pos
+		if err := g.z.Reset(g.cr); err == nil {
+			g.z.Multistream(false)
+			return
+		}
+		offset = pos + 1
+	}
+}
+
+// findMagic returns the offset of the next gzip member header; start is the
+// offset of the reader in the file.
+func findMagic(r *bufio.Reader, start int64) (int64, bool) {
+	matched := 0
+	for pos := start; ; pos++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, false
+		}
+		switch {
+		case b == gzipMagic[matched]:
+			matched++
+		case b == gzipMagic[0]:
+			matched = 1
+		default:
+			matched = 0
+		}
+		if matched == len(gzipMagic) {
+			return pos - int64(len(gzipMagic)) + 1, true
+		}
+	}
+}
+
+func (g *gzipFile) Partial() error {
+	if g.err == nil {
+		return nil
+	}
+	if errors.Is(g.err, io.ErrUnexpectedEOF) && g.skipped <= 1 {
+		return fmt.Errorf("%w: %v is truncated", ErrPartialFile, g.name)
+	}
+	return fmt.Errorf("%w: %v: %v corrupted part(s) skipped: %v", ErrPartialFile, g.name, g.skipped, g.err)
+}
+
+func (g *gzipFile) Close() error {
+	return g.file.Close()
+}
@@ -0,0 +1,92 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gzipMember(t *testing.T, prefix string, lines int) []byte {
+	var content strings.Builder
+	for i := 0; i < lines; i++ {
+		fmt.Fprintf(&content, "%v line %v\n", prefix, i)
+	}
+	return gzipData(t, content.String())
+}
+
+func gzipData(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// readLog writes the data as the Mesos master log and reads it back.
+func readLog(t *testing.T, data []byte) (string, error) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "dcos-mesos-master.service.gz"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	d := Directory{Type: DTMaster, Path: dir}
+	f, err := d.OpenFile("mesos-master-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("Expected no read errors, observed %v", err)
+	}
+	return string(content), Partial(f)
+}
+
+func TestGzipMultipleMembers(t *testing.T) {
+	data := append(gzipMember(t, "first", 100), gzipMember(t, "second", 100)...)
+	content, err := readLog(t, data)
+	if err != nil {
+		t.Errorf("Expected the file to be read completely, observed %v", err)
+	}
+	if n := strings.Count(content, "\n"); n != 200 {
+		t.Errorf("Expected 200 lines, observed %v", n)
+	}
+}
+
+func TestGzipTruncated(t *testing.T) {
+	data := gzipMember(t, "first", 10000)
+	content, err := readLog(t, data[:len(data)/2])
+	if !errors.Is(err, ErrPartialFile) {
+		t.Errorf("Expected %v, observed %v", ErrPartialFile, err)
+	}
+	if !strings.HasPrefix(content, "first line 0\nfirst line 1\n") {
+		t.Errorf("Expected the beginning of the file to be read, observed %.50q", content)
+	}
+}
+
+func TestGzipCorruptedMember(t *testing.T) {
+	first := gzipMember(t, "first", 100)
+	second := gzipMember(t, "second", 1000)
+	third := gzipMember(t, "third", 100)
+	for i := len(second) / 3; i < len(second)/2; i++ {
+		second[i] = 0xff
+	}
+	data := append(append(first, second...), third...)
+	content, err := readLog(t, data)
+	if !errors.Is(err, ErrPartialFile) {
+		t.Errorf("Expected %v, observed %v", ErrPartialFile, err)
+	}
+	for _, line := range []string{"first line 0\n", "first line 99\n", "third line 0\n", "third line 99\n"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected %q to be read", line)
+		}
+	}
+}
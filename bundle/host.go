@@ -0,0 +1,126 @@
+package bundle
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// AddressFamily is a family of the host address.
+type AddressFamily string
+
+const (
+	// AFIPv4 is an IPv4 address.
+	AFIPv4 AddressFamily = "IPv4"
+	// AFIPv6 is an IPv6 address.
+	AFIPv6 = "IPv6"
+	// AFHostname means that the host is identified by its DNS name only.
+	AFHostname = "hostname"
+)
+
+// IP identifies a host in a DC/OS cluster. IPs are comparable, so they can be
+// used as map keys; use ParseIP to create them.
+type IP struct {
+	// Address is the canonical form of the IPv4 or IPv6 address; it's empty
+	// if the host is identified by its hostname only.
+	Address string
+	Family  AddressFamily
+	// Hostname is optional.
+	Hostname string
+}
+
+// ParseIP creates a host identity from an IPv4 or IPv6 address or a DNS name.
+// IPv6 addresses may be enclosed in square brackets. IPv4-mapped IPv6
+// addresses, e.g. ::ffff:10.0.0.1, are IPv4 addresses, so the same host has
+// the same identity in both forms.
+func ParseIP(s string) IP {
+	if ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")); ip != nil {
+		if v4 := ip.To4(); v4 != nil {
+			return IP{Address: v4.String(), Family: AFIPv4}
+		}
+		return IP{Address: ip.String(), Family: AFIPv6}
+	}
+	if s == "" {
+		return IP{}
+	}
+	return IP{Family: AFHostname, Hostname: strings.ToLower(s)}
+}
+
+// String returns the address or the hostname if the address is unknown.
+func (ip IP) String() string {
+	if ip.Address != "" {
+		return ip.Address
+	}
+	return ip.Hostname
+}
+
+// IsZero returns true if the host identity is not set.
+func (ip IP) IsZero() bool {
+	return ip == IP{}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, so IPs are
+// represented as strings in JSON and YAML.
+func (ip IP) MarshalText() ([]byte, error) {
+	return []byte(ip.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (ip *IP) UnmarshalText(text []byte) error {
+	*ip = ParseIP(string(text))
+	return nil
+}
+
+// hostnameRegexp matches DNS names, e.g. ip-10-0-0-1.us-west-2.compute.internal.
+var hostnameRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+
+// hostDirSuffixes map host directory name suffixes to the directory types.
+// agent_public goes before agent because both end with "agent".
+var hostDirSuffixes = []struct {
+	suffix  string
+	dirType DirType
+}{
+	{"_master", DTMaster},
+	{"_agent_public", DTPublicAgent},
+	{"_agent", DTAgent},
+}
+
+// ParseHostDir parses the name of a host directory in the bundle, e.g.
+// 10.0.0.1_master, fd01::1_agent, or ip-10-0-0-1.ec2.internal_agent_public.
+// It returns false if the name is not a host directory name.
+func ParseHostDir(name string) (IP, DirType, bool) {
+	for _, s := range hostDirSuffixes {
+		if !strings.HasSuffix(name, s.suffix) {
+			continue
+		}
+		id := strings.TrimSuffix(name, s.suffix)
+		ip := ParseIP(id)
+		if ip.Family == AFHostname && !hostnameRegexp.MatchString(id) {
+			return IP{}, "", false
+		}
+		if ip.IsZero() {
+			return IP{}, "", false
+		}
+		return ip, s.dirType, true
+	}
+	return IP{}, "", false
+}
+
+// HostDirName returns the path of the host directory relative to the bundle
+// root; for DC/OS hosts it's the reverse of ParseHostDir. Kubernetes nodes are
+// in the nodes directory and named by the node name.
+func HostDirName(ip IP, dirType DirType) string {
+	if dirType == DTControlPlane || dirType == DTWorker {
+		name := ip.Hostname
+		if name == "" {
+			name = ip.Address
+		}
+		return kubernetesNodesDir + "/" + name
+	}
+	for _, s := range hostDirSuffixes {
+		if s.dirType == dirType {
+			return ip.String() + s.suffix
+		}
+	}
+	return ip.String()
+}
@@ -0,0 +1,77 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseHostDir(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		ip      IP
+		dirType DirType
+		ok      bool
+	}{
+		{"10.0.0.1_master", IP{Address: "10.0.0.1", Family: AFIPv4}, DTMaster, true},
+		{"10.0.0.1_agent", IP{Address: "10.0.0.1", Family: AFIPv4}, DTAgent, true},
+		{"10.0.0.1_agent_public", IP{Address: "10.0.0.1", Family: AFIPv4}, DTPublicAgent, true},
+		{"fd01:0::1_agent", IP{Address: "fd01::1", Family: AFIPv6}, DTAgent, true},
+		{"[fd01::1]_master", IP{Address: "fd01::1", Family: AFIPv6}, DTMaster, true},
+		{"::ffff:10.0.0.1_master", IP{Address: "10.0.0.1", Family: AFIPv4}, DTMaster, true},
+		{"[::ffff:a00:1]_agent", IP{Address: "10.0.0.1", Family: AFIPv4}, DTAgent, true},
+		{"ip-10-0-0-1.EC2.internal_agent_public",
+			IP{Family: AFHostname, Hostname: "ip-10-0-0-1.ec2.internal"}, DTPublicAgent, true},
+		{"10.0.0.1_unknown", IP{}, "", false},
+		{"_agent", IP{}, "", false},
+		{"not a host_agent", IP{}, "", false},
+		{"logs", IP{}, "", false},
+	} {
+		ip, dirType, ok := ParseHostDir(c.name)
+		if ip != c.ip || dirType != c.dirType || ok != c.ok {
+			t.Errorf("ParseHostDir(%q): expected %+v, %q, %v; observed %+v, %q, %v",
+				c.name, c.ip, c.dirType, c.ok, ip, dirType, ok)
+		}
+	}
+}
+
+func TestNewWithIPv6AndHostnames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"fd01::1_master", "ip-10-0-0-2.ec2.internal_agent"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Masters()) != 1 || b.Masters()[0].IP.Family != AFIPv6 {
+		t.Errorf("Expected an IPv6 master, observed %+v", b.Masters())
+	}
+	if len(b.Agents()) != 1 || b.Agents()[0].IP.String() != "ip-10-0-0-2.ec2.internal" {
+		t.Errorf("Expected an agent identified by its hostname, observed %+v", b.Agents())
+	}
+}
+
+func FuzzParseHostDir(f *testing.F) {
+	for _, name := range []string{"10.0.0.1_master", "fd01:0::1_agent", "[fd01::1]_master",
+		"::ffff:10.0.0.1_master", "ip-10-0-0-1.EC2.internal_agent_public", "_agent", "logs"} {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		ip, dirType, ok := ParseHostDir(name)
+		if !ok {
+			return
+		}
+		if ip.IsZero() {
+			t.Fatalf("ParseHostDir(%q) returned an empty IP", name)
+		}
+		dirName := HostDirName(ip, dirType)
+		ip2, dirType2, ok := ParseHostDir(dirName)
+		if !ok || ip2 != ip || dirType2 != dirType {
+			t.Errorf("ParseHostDir(%q) = %+v, %q; ParseHostDir(%q) = %+v, %q, %v",
+				name, ip, dirType, dirName, ip2, dirType2, ok)
+		}
+	})
+}
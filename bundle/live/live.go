@@ -0,0 +1,167 @@
+// Package live builds a virtual bundle from the APIs of a running DC/OS
+// cluster, so the checks which read JSON file types can run without a
+// diagnostics bundle.
+package live
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+// Endpoints maps the file types available in the live mode to the paths of
+// the cluster APIs behind Admin Router which return them.
+var Endpoints = map[bundle.FileTypeName]string{
+	"mesos-master-state":      "/mesos/master/state",
+	"mesos-master-frameworks": "/mesos/master/frameworks",
+	"mesos-master-agents":     "/mesos/master/slaves",
+	"marathon-apps":           "/marathon/v2/apps",
+	"marathon-deployments":    "/marathon/v2/deployments",
+	"vips":                    "/net/v1/vips",
+	"diagnostics-health":      "/system/health/v1",
+}
+
+// Options configures the connection to the cluster.
+type Options struct {
+	// URL is the URL of the cluster, e.g. https://dcos.example.com.
+	URL string
+	// Token is the DC/OS authentication token; it's not sent if empty.
+	Token string
+	// Client is the HTTP client; http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+// New returns a bundle with the Mesos leader as the only master; its files
+// are fetched from the cluster when they are opened for the first time and
+// cached afterwards. The requests are canceled when the ctx is done.
+func New(ctx context.Context, opts Options) (bundle.Bundle, error) {
+	base, err := url.Parse(strings.TrimSuffix(opts.URL, "/"))
+	if err != nil {
+		return bundle.Bundle{}, fmt.Errorf("invalid cluster URL %v: %w", opts.URL, err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return bundle.Bundle{}, fmt.Errorf("invalid cluster URL %v: expected an http or https URL", opts.URL)
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	s := &source{
+		ctx:   ctx,
+		opts:  opts,
+		base:  base,
+		cache: make(map[bundle.FileTypeName]*response),
+	}
+	root := bundle.NewVirtualDirectory(bundle.DTRoot, base.String(), s)
+	master := bundle.Host{Directory: bundle.NewVirtualDirectory(bundle.DTMaster, base.String(), s)}
+	// The Mesos leader identifies itself by the hostname in the state, see
+	// cluster.NewMesosMaster.
+	var state struct {
+		Hostname string `json:"hostname"`
+	}
+	if err := master.ReadJSON("mesos-master-state", &state); err != nil {
+		return bundle.Bundle{}, fmt.Errorf("cannot read the Mesos state of the cluster: %w", err)
+	}
+	master.IP = bundle.ParseIP(state.Hostname)
+	if master.IP.IsZero() {
+		master.IP = bundle.ParseIP(base.Hostname())
+	}
+	return bundle.Bundle{Flavor: bundle.FDCOS, Hosts: []bundle.Host{master}, Directory: root}, nil
+}
+
+// response is a cached API response.
+type response struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// source fetches the files from the cluster APIs.
+type source struct {
+	ctx  context.Context
+	opts Options
+	base *url.URL
+
+	mu    sync.Mutex
+	cache map[bundle.FileTypeName]*response
+}
+
+func (s *source) Location(t bundle.FileTypeName) (string, bool) {
+	path, ok := Endpoints[t]
+	if !ok {
+		return "", false
+	}
+	u := *s.base
+	u.Path += path
+	return u.String(), true
+}
+
+func (s *source) Open(t bundle.FileTypeName) (bundle.File, error) {
+	location, ok := s.Location(t)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v is not available from the cluster", bundle.ErrFileNotFound, t)
+	}
+	s.mu.Lock()
+	r, ok := s.cache[t]
+	if !ok {
+		r = &response{}
+		s.cache[t] = r
+	}
+	s.mu.Unlock()
+	r.once.Do(func() {
+		r.data, r.err = s.get(location)
+	})
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &file{Reader: bytes.NewReader(r.data), name: location}, nil
+}
+
+// get returns the body of the response. It returns an error wrapping
+// bundle.ErrFileNotFound if the API is not found, e.g. Marathon is not
+// reachable.
+func (s *source) get(location string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.opts.Token != "" {
+		req.Header.Set("Authorization", "token="+s.opts.Token)
+	}
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: GET %v responded with %v", bundle.ErrFileNotFound, location, resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GET %v responded with %v: %v", location, resp.Status,
+			strings.TrimSpace(string(body)))
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// file is an API response read as a bundle file.
+type file struct {
+	*bytes.Reader
+	name string
+}
+
+func (f *file) Name() string {
+	return f.name
+}
+
+func (f *file) Close() error {
+	return nil
+}
@@ -0,0 +1,108 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+	_ "github.com/mesosphere/bun/v2/checks/marathon/deployments"
+	"github.com/mesosphere/bun/v2/cluster"
+)
+
+const token = "secret-token"
+
+// responses are the bodies of the fake cluster APIs.
+var responses = map[string]string{
+	"/mesos/master/state":      `{"hostname": "10.0.0.1", "version": "1.10.0"}`,
+	"/marathon/v2/deployments": `[{"id": "1"}, {"id": "2"}, {"id": "3"}]`,
+	"/system/health/v1":        `{"units": [{"id": "dcos-mesos-master.service", "health": 0}]}`,
+}
+
+// fakeCluster serves the responses and counts the requests by the path.
+type fakeCluster struct {
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func (c *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "token="+token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	c.mu.Lock()
+	c.requests[r.URL.Path]++
+	c.mu.Unlock()
+	body, ok := responses[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write([]byte(body))
+}
+
+func newBundle(t *testing.T) (bundle.Bundle, *fakeCluster) {
+	c := &fakeCluster{requests: make(map[string]int)}
+	ts := httptest.NewServer(c)
+	t.Cleanup(ts.Close)
+	b, err := New(context.Background(), Options{URL: ts.URL, Token: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, c
+}
+
+func TestNew(t *testing.T) {
+	b, _ := newBundle(t)
+	if len(b.Masters()) != 1 || b.Masters()[0].IP != bundle.ParseIP("10.0.0.1") {
+		t.Fatalf("Expected the master 10.0.0.1, observed %v", b.Hosts)
+	}
+	leader, err := cluster.New(b).MesosLeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leader.IP() != bundle.ParseIP("10.0.0.1") {
+		t.Errorf("Expected the leader 10.0.0.1, observed %v", leader.IP())
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	b, c := newBundle(t)
+	check, err := checks.GetCheck("marathon-deployments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		results := check.Run(b)
+		if len(results) != 1 || results[0].Status != checks.SOK || !strings.Contains(results[0].Value.(string), "has 3 deployments") {
+			t.Fatalf("Expected OK with 3 deployments, observed %v", results)
+		}
+	}
+	if n := c.requests["/marathon/v2/deployments"]; n != 1 {
+		t.Errorf("Expected the deployments to be fetched once, observed %v requests", n)
+	}
+}
+
+func TestFileNotFound(t *testing.T) {
+	b, _ := newBundle(t)
+	var apps interface{}
+	if err := b.Masters()[0].ReadJSON("marathon-apps", &apps); !errors.Is(err, bundle.ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound for a missing API, observed %v", err)
+	}
+	if _, err := b.Masters()[0].OpenFile("mesos-master-roles"); !errors.Is(err, bundle.ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound for a file type without an API, observed %v", err)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	ts := httptest.NewServer(&fakeCluster{requests: make(map[string]int)})
+	defer ts.Close()
+	if _, err := New(context.Background(), Options{URL: ts.URL, Token: "wrong"}); err == nil {
+		t.Error("Expected an error for a wrong token")
+	}
+}
@@ -0,0 +1,234 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rotationRegexp matches the numeric suffix of rotated files, e.g.
+// mesos-agent.log.1, dmesg-1.output, or dcos-mesos-master.service.2.
+var rotationRegexp = regexp.MustCompile(`[.-]([0-9]+)(\.[A-Za-z]+)?$`)
+
+// rotationIndex returns the numeric suffix of the rotated file or -1 if
+// the file has no suffix; the greater the index, the older the file.
+func rotationIndex(filePath string) int {
+	groups := rotationRegexp.FindStringSubmatch(path.Base(TrimCompressedExt(filePath)))
+	if groups == nil {
+		return -1
+	}
+	i, err := strconv.Atoi(groups[1])
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
+// sortChronologically sorts the rotated files from the oldest to the newest.
+func sortChronologically(paths []string) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		ri, rj := rotationIndex(paths[i]), rotationIndex(paths[j])
+		if ri != rj {
+			return ri > rj
+		}
+		return paths[i] < paths[j]
+	})
+}
+
+// FilePaths returns the paths of the physical files of the typeName file type.
+// For file types with AllMatches set it returns all the matching files from
+// the oldest to the newest; otherwise it returns the first file which exists.
+// If the directory is merged from several physical directories, they are
+// searched in the order they were merged.
+// If a path is a glob pattern and AllMatches is not set, the newest match is
+// used. For virtual directories it returns the location of the file in the
+// source. It returns ErrFileNotFound if there are no such files.
+func (d Directory) FilePaths(typeName FileTypeName) ([]string, error) {
+	fileType, err := GetFileType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if !fileType.ExistsOn(d.Type) {
+		return nil, fmt.Errorf("%w: %v files cannot be found on %v hosts", ErrWrongDirType, typeName, d.Type)
+	}
+	if d.source != nil {
+		return d.sourcePaths(fileType)
+	}
+	var found []string
+	// seen are the files relative to the root found in the previous roots;
+	// the same file in a merged directory is taken from the first root.
+	seen := make(map[string]bool)
+	for _, root := range d.Paths() {
+		var rootFound []string
+		for _, localPath := range fileType.Paths {
+			matches, err := match(root, localPath)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				continue
+			}
+			if !fileType.AllMatches {
+				return matches[len(matches)-1:], nil
+			}
+			rootFound = append(rootFound, matches...)
+		}
+		for _, p := range dedupCompressed(rootFound) {
+			rel, err := filepath.Rel(root, TrimCompressedExt(p))
+			if err != nil || !seen[rel] {
+				found = append(found, p)
+			}
+		}
+		for _, p := range rootFound {
+			if rel, err := filepath.Rel(root, TrimCompressedExt(p)); err == nil {
+				seen[rel] = true
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrFileNotFound, strings.Join(fileType.Paths, ", "))
+	}
+	sortChronologically(found)
+	return found, nil
+}
+
+// match returns the existing files in the root directory which match the local
+// path. If a file is not found, it looks for the correspondent compressed file.
+// Matches are sorted chronologically.
+func match(root string, localPath string) ([]string, error) {
+	filePath := filepath.Join(root, localPath)
+	candidates := []string{filePath}
+	for _, ext := range CompressedExtensions() {
+		candidates = append(candidates, filePath+ext)
+	}
+	if !isGlob(localPath) {
+		for _, c := range candidates {
+			_, err := os.Stat(c)
+			if err == nil {
+				return []string{c}, nil // found
+			}
+			if !os.IsNotExist(err) {
+				return nil, err // error
+			}
+		}
+		return nil, nil // not found
+	}
+	var matches []string
+	for _, c := range candidates {
+		m, err := filepath.Glob(c)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFileType, err)
+		}
+		for _, p := range m {
+			if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+				matches = append(matches, p)
+			}
+		}
+	}
+	matches = dedupCompressed(matches)
+	sortChronologically(matches)
+	return matches, nil
+}
+
+// dedupCompressed removes compressed files if there is an uncompressed or
+// another compressed version of the same file; the uncompressed files are
+// preferred, then the compressed ones in the order decompressors were registered.
+func dedupCompressed(paths []string) []string {
+	rank := func(p string) int {
+		ext := path.Ext(p)
+		for i, e := range CompressedExtensions() {
+			if e == ext {
+				return i + 1
+			}
+		}
+		return 0
+	}
+	chosen := make(map[string]string, len(paths))
+	order := make([]string, 0, len(paths))
+	for _, p := range paths {
+		key := TrimCompressedExt(p)
+		existing, ok := chosen[key]
+		if !ok {
+			order = append(order, key)
+			chosen[key] = p
+			continue
+		}
+		if rank(p) < rank(existing) {
+			chosen[key] = p
+		}
+	}
+	result := make([]string, 0, len(order))
+	for _, key := range order {
+		result = append(result, chosen[key])
+	}
+	return result
+}
+
+func isGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// multiFile reads several physical files as one logical stream.
+type multiFile struct {
+	files   []File
+	current int
+}
+
+// Name returns the name of the newest physical file.
+func (m *multiFile) Name() string {
+	return m.files[len(m.files)-1].Name()
+}
+
+// Files returns the physical files from the oldest to the newest.
+func (m *multiFile) Files() []File {
+	return m.files
+}
+
+func (m *multiFile) Read(p []byte) (int, error) {
+	for m.current < len(m.files) {
+		n, err := m.files[m.current].Read(p)
+		if err == io.EOF {
+			m.current++
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+// Partial returns the first error of the partially read physical files.
+func (m *multiFile) Partial() error {
+	for _, f := range m.files {
+		if err := Partial(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *multiFile) Close() error {
+	return closeFiles(m.files)
+}
+
+func closeFiles(files []File) error {
+	var e []string
+	for _, f := range files {
+		if err := f.Close(); err != nil {
+			e = append(e, err.Error())
+		}
+	}
+	if len(e) > 0 {
+		return errors.New(strings.Join(e, "\n"))
+	}
+	return nil
+}
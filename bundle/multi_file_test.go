@@ -0,0 +1,109 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// rotatedLogs creates the Mesos agent log rotated twice; the oldest file is compressed.
+func rotatedLogs(t *testing.T) Directory {
+	dir := t.TempDir()
+	logDir := filepath.Join(dir, "var", "log", "mesos")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write([]byte("oldest\n"))
+	_ = w.Close()
+	for name, content := range map[string][]byte{
+		"mesos-agent.log.2.gz": buf.Bytes(),
+		"mesos-agent.log.1":    []byte("older\n"),
+		"mesos-agent.log":      []byte("newest 1\nnewest 2\n"),
+	} {
+		if err := ioutil.WriteFile(filepath.Join(logDir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Directory{Type: DTAgent, Path: dir}
+}
+
+func TestScanFilesAllMatches(t *testing.T) {
+	d := rotatedLogs(t)
+	var lines, files []string
+	var numbers []int
+	_, err := d.ScanFiles("mesos-agent-var-log", func(f File, n int, line string) bool {
+		lines = append(lines, line)
+		files = append(files, filepath.Base(f.Name()))
+		numbers = append(numbers, n)
+		return false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedLines := []string{"oldest\n", "older\n", "newest 1\n", "newest 2\n"}
+	expectedFiles := []string{"mesos-agent.log.2.gz", "mesos-agent.log.1", "mesos-agent.log", "mesos-agent.log"}
+	expectedNumbers := []int{1, 1, 1, 2}
+	for i := range expectedLines {
+		if i >= len(lines) {
+			t.Fatalf("Expected %v lines, observed %v", len(expectedLines), len(lines))
+		}
+		if lines[i] != expectedLines[i] || files[i] != expectedFiles[i] || numbers[i] != expectedNumbers[i] {
+			t.Errorf("Line %v: expected %q from %v:%v, observed %q from %v:%v", i,
+				expectedLines[i], expectedFiles[i], expectedNumbers[i], lines[i], files[i], numbers[i])
+		}
+	}
+}
+
+func TestScanLinesNumbersAcrossFiles(t *testing.T) {
+	d := rotatedLogs(t)
+	var numbers []int
+	_, err := d.ScanLines("mesos-agent-var-log", func(n int, line string) bool {
+		if line == "" {
+			t.Errorf("Unexpected empty line %v", n)
+		}
+		numbers = append(numbers, n)
+		return false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(numbers, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected line numbers [1 2 3 4], observed %v", numbers)
+	}
+}
+
+func TestOpenFileAllMatches(t *testing.T) {
+	d := rotatedLogs(t)
+	f, err := d.OpenFile("mesos-agent-var-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "oldest\nolder\nnewest 1\nnewest 2\n" {
+		t.Errorf("Unexpected content: %q", content)
+	}
+	if filepath.Base(f.Name()) != "mesos-agent.log" {
+		t.Errorf("Expected the name of the newest file, observed %v", f.Name())
+	}
+}
+
+func TestSortChronologically(t *testing.T) {
+	paths := []string{"dmesg-0.output", "dmesg-10.output", "dmesg-2.output", "mesos.log", "mesos.log.1.gz"}
+	sortChronologically(paths)
+	expected := []string{"dmesg-10.output", "dmesg-2.output", "mesos.log.1.gz", "dmesg-0.output", "mesos.log"}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Fatalf("Expected %v, observed %v", expected, paths)
+		}
+	}
+}
@@ -13,23 +13,37 @@ type yamlFile struct {
 	Paths       []string `yaml:"paths"`
 	Description string   `yaml:"description"`
 	DirTypes    []string `yaml:"dirTypes"`
+	AllMatches  bool     `yaml:"allMatches"`
 }
 
 //go:embed file_types.yaml
 var filesYAML []byte
 
+//go:embed file_types_kubernetes.yaml
+var kubernetesFilesYAML []byte
+
 func init() {
+	mustRegisterFileTypes(filesYAML, FDCOS)
+	mustRegisterFileTypes(kubernetesFilesYAML, FKubernetes)
+}
+
+// mustRegisterFileTypes registers the file types of the flavor described in
+// the YAML document.
+func mustRegisterFileTypes(y []byte, f Flavor) {
 	var files []yamlFile
-	err := yaml.Unmarshal(filesYAML, &files)
+	err := yaml.Unmarshal(y, &files)
 	if err != nil {
 		panic(err)
 	}
 	for _, file := range files {
 		fileType, err := convert(file)
+		fileType.Flavor = f
 		if err != nil {
 			panic(err)
 		}
-		RegisterFileType(fileType)
+		if err := RegisterFileType(fileType); err != nil {
+			panic(err)
+		}
 	}
 }
 
@@ -39,6 +53,8 @@ func convert(y yamlFile) (fileType FileType, err error) {
 	switch y.ContentType {
 	case string(CTJson):
 		fileType.ContentType = CTJson
+	case string(CTYaml):
+		fileType.ContentType = CTYaml
 	case "journal":
 		fileType.ContentType = CTJournal
 	case "dmesg":
@@ -59,6 +75,7 @@ func convert(y yamlFile) (fileType FileType, err error) {
 		fileType.DirTypes = append(fileType.DirTypes, dirType)
 	}
 	fileType.Paths = y.Paths
+	fileType.AllMatches = y.AllMatches
 	return
 }
 
@@ -72,6 +89,10 @@ func convertDirType(s string) (d DirType, err error) {
 		d = DTAgent
 	case "public agent":
 		d = DTPublicAgent
+	case "control plane":
+		d = DTControlPlane
+	case "worker":
+		d = DTWorker
 	default:
 		err = fmt.Errorf("unknown DirType: %v", s)
 	}
@@ -0,0 +1,29 @@
+package bundle
+
+import "fmt"
+
+// Source provides the files of a virtual directory, i.e. a directory which is
+// not on the filesystem, e.g. the API responses of a live cluster.
+type Source interface {
+	// Location returns where the file of the type comes from, e.g. a URL, or
+	// false if the source doesn't provide the file type.
+	Location(t FileTypeName) (string, bool)
+	// Open opens the file of the type. It's called only for the file types
+	// which the source provides.
+	Open(t FileTypeName) (File, error)
+}
+
+// NewVirtualDirectory returns a directory of the dirType type which files are
+// provided by the source. The path identifies the directory in messages.
+func NewVirtualDirectory(dirType DirType, path string, source Source) Directory {
+	return Directory{Type: dirType, Path: path, source: source}
+}
+
+// sourcePaths returns the location of the file of the type in the source.
+func (d Directory) sourcePaths(fileType FileType) ([]string, error) {
+	location, ok := d.source.Location(fileType.Name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v is not available from %v", ErrFileNotFound, fileType.Name, d.Path)
+	}
+	return []string{location}, nil
+}
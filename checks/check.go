@@ -1,6 +1,13 @@
 package checks
 
-import "github.com/mesosphere/bun/v2/bundle"
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
 
 // Status defines possible check outcomes.
 type Status string
@@ -12,10 +19,13 @@ const (
 	SOK = "OK"
 	// SProblem means that the bundle failed to pass the check.
 	SProblem = "PROBLEM"
+	// SSkipped means that the check wasn't performed because the bundle
+	// doesn't contain the files it needs, e.g. they weren't collected.
+	SSkipped = "SKIPPED"
 )
 
-// Check checks some aspect of the DC/OS cluster analyzing its diagnostics
-// bundle.
+// Check checks some aspect of the DC/OS or Kubernetes cluster analyzing its
+// diagnostics bundle.
 // Checks can be registered in the check registry with the registerCheck function.
 type Check struct {
 	Name           string          `yaml:"name"`           // Required
@@ -23,20 +33,124 @@ type Check struct {
 	Cure           string          `yaml:"cure"`           // Required
 	OKSummary      string          `yaml:"okSummary"`      // Optional
 	ProblemSummary string          `yaml:"problemSummary"` // Optional
+	Tags           []string        `yaml:"tags"`           // Optional
+	Params         Params          `yaml:"-"`              // Optional
+	Flavor         bundle.Flavor   `yaml:"flavor"`         // Optional, DC/OS if empty; see AppliesTo
+	Doc            Doc             `yaml:"doc"`            // Optional, shown by bun explain
 	Run            CheckBundleFunc // Required
+	// Requires are the types of the files the check needs. The check is
+	// skipped if the bundle has no files of any of these types. Optional.
+	Requires []bundle.FileTypeName `yaml:"requires"`
+}
+
+// AppliesTo returns true if the check is meant for bundles of the bundle
+// flavor. Checks apply to bundles with an unknown flavor.
+func (c Check) AppliesTo(b bundle.Bundle) bool {
+	flavor := c.Flavor
+	if flavor == "" {
+		flavor = bundle.FDCOS
+	}
+	return b.Flavor == "" || b.Flavor == flavor
 }
 
 type CheckBundleFunc func(bundle.Bundle) Results
 
+// SafeRun runs the check. If the bundle has no files of one of the required
+// types, SafeRun returns a SKIPPED result without running the check. The
+// UNDEFINED results of the hosts which have no files of one of the required
+// types become SKIPPED. If the check panics, SafeRun recovers and returns an
+// UNDEFINED result with the *PanicError value.
+func (c Check) SafeRun(b bundle.Bundle) (results Results) {
+	if missing := c.MissingFiles(b); len(missing) > 0 {
+		return Results{{
+			Status:   SSkipped,
+			Value:    fmt.Sprintf("Skipped: the bundle has no %v files", joinFileTypes(missing)),
+			Evidence: missing,
+		}}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			results = Results{{
+				Status: SUndefined,
+				Value:  &PanicError{Check: c.Name, Value: r, Stack: debug.Stack()},
+			}}
+		}
+	}()
+	return markPartial(c.skipHostsWithoutFiles(c.Run(b)))
+}
+
+// markPartial flags the results whose value is an error caused by a partially
+// read file, e.g. the one returned by Directory.ReadJSON.
+func markPartial(results Results) Results {
+	for i, r := range results {
+		if err, ok := r.Value.(error); ok && errors.Is(err, bundle.ErrPartialFile) {
+			results[i].Partial = true
+		}
+	}
+	return results
+}
+
+// skipHostsWithoutFiles replaces the UNDEFINED results of the hosts which have
+// no files of the required types with SKIPPED results.
+func (c Check) skipHostsWithoutFiles(results Results) Results {
+	for i, r := range results {
+		if r.Status != SUndefined || !r.IsHostSet() {
+			continue
+		}
+		var missing []bundle.FileTypeName
+		for _, t := range c.Requires {
+			if _, err := r.Host.FilePaths(t); errors.Is(err, bundle.ErrFileNotFound) {
+				missing = append(missing, t)
+			}
+		}
+		if len(missing) > 0 {
+			results[i] = Result{
+				Status:   SSkipped,
+				Host:     r.Host,
+				Value:    fmt.Sprintf("Skipped: the host has no %v files", joinFileTypes(missing)),
+				Evidence: missing,
+			}
+		}
+	}
+	return results
+}
+
+// MissingFiles returns the required file types of which the bundle has no
+// files. Unknown file types are not reported, so the check can report them.
+func (c Check) MissingFiles(b bundle.Bundle) []bundle.FileTypeName {
+	var missing []bundle.FileTypeName
+	for _, t := range c.Requires {
+		if found, err := b.HasFiles(t); err == nil && !found {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
+
+func joinFileTypes(types []bundle.FileTypeName) string {
+	s := make([]string, 0, len(types))
+	for _, t := range types {
+		s = append(s, string(t))
+	}
+	return strings.Join(s, ", ")
+}
+
 // Result represents check result.
 type Result struct {
 	Status Status
 	Value  interface{}
 	Host   bundle.Host
+	// Partial is true if the result is based on a partially read file, e.g.
+	// a truncated .gz archive. SafeRun sets it for the results whose value is
+	// an error wrapping bundle.ErrPartialFile.
+	Partial bool
+	// Evidence are the types of the files the result is based on, so they
+	// can be shown to the user, e.g. by bun ui. It's optional.
+	Evidence []bundle.FileTypeName
 }
 
 func (r Result) IsHostSet() bool {
-	return r.Host.IP != ""
+	return !r.Host.IP.IsZero()
 }
 
 type Results []Result
@@ -63,6 +177,13 @@ func (r Results) OKs() Results {
 	return r.filter(SOK)
 }
 
+func (r Results) Skipped() Results {
+	return r.filter(SSkipped)
+}
+
+// Status returns the aggregated status: PROBLEM if any of the results is a
+// problem, then UNDEFINED, then SKIPPED if all the results are skipped, and
+// OK otherwise. Skipped hosts don't fail a check which passed on other hosts.
 func (r Results) Status() Status {
 	if len(r.Problems()) > 0 {
 		return SProblem
@@ -70,5 +191,18 @@ func (r Results) Status() Status {
 	if len(r.Undefined()) > 0 {
 		return SUndefined
 	}
+	if len(r) > 0 && len(r.Skipped()) == len(r) {
+		return SSkipped
+	}
 	return SOK
 }
+
+// Partial returns true if any of the results is based on a partially read file.
+func (r Results) Partial() bool {
+	for _, result := range r {
+		if result.Partial {
+			return true
+		}
+	}
+	return false
+}
@@ -6,29 +6,41 @@ import (
 
 // CheckFuncBuilder helps to create map/reduce-like checks.
 type CheckFuncBuilder struct {
-	CheckMasters      CheckHostFunc // At least one of
-	CheckAgents       CheckHostFunc // the Collect... functions
-	CheckPublicAgents CheckHostFunc // are required
-	Aggregate         Aggregate     // Implement if the default is not sufficient
+	CheckMasters       CheckHostFunc // At least one of
+	CheckAgents        CheckHostFunc // the Collect... functions
+	CheckPublicAgents  CheckHostFunc // are required
+	CheckControlPlanes CheckHostFunc
+	CheckWorkers       CheckHostFunc
+	Aggregate          Aggregate // Implement if the default is not sufficient
 }
 
 // CheckHostFunc checks an individual host.
 type CheckHostFunc func(bundle.Host) Result
 
-// Aggregate aggregates check results produced by CheckMaster, CheckAgents, and CheckPublicAgents functions.
+// Aggregate aggregates check results produced by the CheckHostFunc functions.
 type Aggregate func(results Results) Results
 
-// Build returns a check function Run.Check
-func (b CheckFuncBuilder) Build() CheckBundleFunc {
+// Build returns a check function Run.Check. It returns ErrNoCheckHostFunc
+// if none of the CheckHostFunc functions is specified.
+func (b CheckFuncBuilder) Build() (CheckBundleFunc, error) {
 	if b.Aggregate == nil {
 		b.Aggregate = DefaultAggregate
 	}
 	if b.CheckMasters == nil && b.CheckAgents == nil &&
-		b.CheckPublicAgents == nil {
-		panic("bun.CheckFuncBuilder.Build: At least one of the CheckHostFunc functions" +
-			"should be specified")
+		b.CheckPublicAgents == nil && b.CheckControlPlanes == nil && b.CheckWorkers == nil {
+		return nil, ErrNoCheckHostFunc
 	}
-	return b.checkFunc
+	return b.checkFunc, nil
+}
+
+// MustBuild is like Build but panics if the check function cannot be built.
+// It simplifies creation of the built-in checks in the init functions.
+func (b CheckFuncBuilder) MustBuild() CheckBundleFunc {
+	f, err := b.Build()
+	if err != nil {
+		panic("bun.CheckFuncBuilder.MustBuild: " + err.Error())
+	}
+	return f
 }
 
 // Default implementation of the Aggregate function.
@@ -56,5 +68,11 @@ func (b CheckFuncBuilder) checkFunc(bundle bundle.Bundle) (results Results) {
 	if b.CheckPublicAgents != nil {
 		checkHosts(bundle.PublicAgents(), b.CheckPublicAgents, &results)
 	}
+	if b.CheckControlPlanes != nil {
+		checkHosts(bundle.ControlPlanes(), b.CheckControlPlanes, &results)
+	}
+	if b.CheckWorkers != nil {
+		checkHosts(bundle.Workers(), b.CheckWorkers, &results)
+	}
 	return b.Aggregate(results)
 }
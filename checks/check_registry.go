@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+
+	"github.com/mesosphere/bun/v2/bundle"
 )
 
 var (
@@ -12,21 +14,43 @@ var (
 )
 
 // RegisterCheck registers a new check to make it discoverable for consumers.
-func RegisterCheck(c Check) {
+// It returns ErrInvalidCheck if the check is not valid and ErrDuplicateCheck
+// if a check with the same name is already registered.
+func RegisterCheck(c Check) error {
 	checkRegistryMu.Lock()
 	defer checkRegistryMu.Unlock()
 	if c.Name == "" {
-		panic("bun.checks.RegisterCheck: Check Name should not be empty")
+		return fmt.Errorf("%w: check Name should not be empty", ErrInvalidCheck)
 	}
 	if c.Description == "" {
-		panic("bun.checks.RegisterCheck: Check Description should not be empty")
+		return fmt.Errorf("%w: check %v: Description should not be empty", ErrInvalidCheck, c.Name)
+	}
+	if err := checkS(c); err != nil {
+		return err
 	}
-	checkS(c)
 	if c.Cure == "" {
-		panic("bun.checks.RegisterCheck: Check Cure should not be empty")
+		return fmt.Errorf("%w: check %v: Cure should not be empty", ErrInvalidCheck, c.Name)
+	}
+	if c.Run == nil {
+		return fmt.Errorf("%w: check %v: Run should not be nil", ErrInvalidCheck, c.Name)
 	}
 	if _, exists := checkRegistry[c.Name]; exists {
-		panic(fmt.Sprintf("bun.RegisterCheck: called twice for check %v", c.Name))
+		return fmt.Errorf("%w: %v", ErrDuplicateCheck, c.Name)
+	}
+	for _, t := range c.Requires {
+		if _, err := bundle.GetFileType(t); err != nil {
+			return fmt.Errorf("%w: check %v: %v", ErrInvalidCheck, c.Name, err)
+		}
+	}
+	if err := c.Doc.validate(); err != nil {
+		return fmt.Errorf("%w: check %v: %v", ErrInvalidCheck, c.Name, err)
+	}
+	params := make(map[string]struct{}, len(c.Params))
+	for _, p := range c.Params {
+		if _, dup := params[p.Name]; dup {
+			return fmt.Errorf("%w: check %v: duplicate parameter %v", ErrInvalidCheck, c.Name, p.Name)
+		}
+		params[p.Name] = struct{}{}
 	}
 	if c.ProblemSummary == "" {
 		c.ProblemSummary = "Problems were found."
@@ -35,14 +59,24 @@ func RegisterCheck(c Check) {
 		c.OKSummary = "No problems were found."
 	}
 	checkRegistry[c.Name] = c
+	return nil
+}
+
+// MustRegisterCheck is like RegisterCheck but panics if the check cannot be
+// registered. It simplifies registration of the built-in checks in the init functions.
+func MustRegisterCheck(c Check) {
+	if err := RegisterCheck(c); err != nil {
+		panic("bun.checks.MustRegisterCheck: " + err.Error())
+	}
 }
 
-func checkS(c Check) {
-	verb := strings.Fields(c.Description)[0]
-	if !strings.HasSuffix(verb, "s") {
-		panic(fmt.Sprintf("Wrong description for the check \"%s\"."+
-			" Check description should start with a present tense third person singular verb.", c.Name))
+func checkS(c Check) error {
+	fields := strings.Fields(c.Description)
+	if len(fields) == 0 || !strings.HasSuffix(fields[0], "s") {
+		return fmt.Errorf("%w: wrong description for the check \"%s\"."+
+			" Check description should start with a present tense third person singular verb.", ErrInvalidCheck, c.Name)
 	}
+	return nil
 }
 
 // Checks Returns all registered checks.
@@ -56,13 +90,14 @@ func Checks() []Check {
 	return checks
 }
 
-// GetCheck returns check by name.
-func GetCheck(name string) Check {
+// GetCheck returns check by name. It returns ErrUnknownCheck if the check
+// is not registered.
+func GetCheck(name string) (Check, error) {
 	checkRegistryMu.RLock()
 	check, ok := checkRegistry[name]
 	checkRegistryMu.RUnlock()
 	if !ok {
-		panic(fmt.Sprintf("bun.GetCheck: don't have check %v", name))
+		return check, fmt.Errorf("%w: %v", ErrUnknownCheck, name)
 	}
-	return check
+	return check, nil
 }
@@ -0,0 +1,125 @@
+package checks
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+func okFunc(bundle.Bundle) Results {
+	return Results{{Status: SOK}}
+}
+
+func TestRegisterCheckErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		check Check
+		err   error
+	}{
+		{"empty name", Check{Description: "Checks", Cure: "None", Run: okFunc}, ErrInvalidCheck},
+		{"wrong verb", Check{Name: "registry-test-verb", Description: "Check", Cure: "None", Run: okFunc},
+			ErrInvalidCheck},
+		{"no cure", Check{Name: "registry-test-cure", Description: "Checks", Run: okFunc}, ErrInvalidCheck},
+		{"no run", Check{Name: "registry-test-run", Description: "Checks", Cure: "None"}, ErrInvalidCheck},
+		{"duplicate params", Check{Name: "registry-test-params", Description: "Checks", Cure: "None", Run: okFunc,
+			Params: Params{IntParam("a", 1, ""), IntParam("a", 2, "")}}, ErrInvalidCheck},
+		{"unknown required file", Check{Name: "registry-test-requires", Description: "Checks", Cure: "None",
+			Run: okFunc, Requires: []bundle.FileTypeName{"no-such-file-type"}}, ErrInvalidCheck},
+		{"unknown doc file", Check{Name: "registry-test-doc-files", Description: "Checks", Cure: "None",
+			Run: okFunc, Doc: Doc{Files: []bundle.FileTypeName{"no-such-file-type"}}}, ErrInvalidCheck},
+		{"link without URL", Check{Name: "registry-test-doc-links", Description: "Checks", Cure: "None",
+			Run: okFunc, Doc: Doc{Links: []Link{{Title: "MESOS-1"}}}}, ErrInvalidCheck},
+	}
+	for _, test := range tests {
+		if err := RegisterCheck(test.check); !errors.Is(err, test.err) {
+			t.Errorf("%v: expected %v, observed %v", test.name, test.err, err)
+		}
+	}
+	c := Check{Name: "registry-test-duplicate", Description: "Checks", Cure: "None", Run: okFunc}
+	if err := RegisterCheck(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterCheck(c); !errors.Is(err, ErrDuplicateCheck) {
+		t.Errorf("Expected %v, observed %v", ErrDuplicateCheck, err)
+	}
+	if _, err := GetCheck("registry-test-no-such-check"); !errors.Is(err, ErrUnknownCheck) {
+		t.Errorf("Expected %v, observed %v", ErrUnknownCheck, err)
+	}
+}
+
+func TestBuildWithoutHostFunc(t *testing.T) {
+	if _, err := (CheckFuncBuilder{}).Build(); !errors.Is(err, ErrNoCheckHostFunc) {
+		t.Errorf("Expected %v, observed %v", ErrNoCheckHostFunc, err)
+	}
+}
+
+func TestSafeRun(t *testing.T) {
+	c := Check{
+		Name: "registry-test-panic",
+		Run: func(bundle.Bundle) Results {
+			var hosts []bundle.Host
+			_ = hosts[1]
+			return nil
+		},
+	}
+	results := c.SafeRun(bundle.Bundle{})
+	if results.Status() != SUndefined {
+		t.Fatalf("Expected Status = UNDEFINED, observed %v", results.Status())
+	}
+	p, ok := results[0].Value.(*PanicError)
+	if !ok {
+		t.Fatalf("Expected *PanicError value, observed %T", results[0].Value)
+	}
+	if len(p.Stack) == 0 {
+		t.Error("Expected stack trace")
+	}
+}
+
+func TestInvalidSearchChecksAreSkipped(t *testing.T) {
+	y := []byte(`
+- name: search-test-valid
+  description: Checks something
+  fileTypeName: mesos-agent-log
+  errorPattern: 'error'
+  cure: None
+  doc:
+    affectedVersions: [DC/OS 1.12]
+    remediation:
+      - Restart the agent
+    links:
+      - title: MESOS-1
+        url: https://issues.apache.org/jira/browse/MESOS-1
+- name: search-test-unknown-file-type
+  description: Checks something
+  fileTypeName: no-such-file-type
+  errorPattern: 'error'
+  cure: None
+- name: search-test-bad-regexp
+  description: Checks something
+  fileTypeName: mesos-agent-log
+  errorPattern: '(error'
+  isErrorPatternRegexp: true
+  cure: None
+`)
+	err := registerSearchChecksYAML(y)
+	if !errors.Is(err, ErrInvalidCheck) {
+		t.Errorf("Expected %v, observed %v", ErrInvalidCheck, err)
+	}
+	c, err := GetCheck("search-test-valid")
+	if err != nil {
+		t.Errorf("Expected the valid check to be registered: %v", err)
+	}
+	expectedDoc := Doc{
+		AffectedVersions: []string{"DC/OS 1.12"},
+		Remediation:      []string{"Restart the agent"},
+		Links:            []Link{{Title: "MESOS-1", URL: "https://issues.apache.org/jira/browse/MESOS-1"}},
+	}
+	if !reflect.DeepEqual(c.Doc, expectedDoc) {
+		t.Errorf("Expected doc %v, observed %v", expectedDoc, c.Doc)
+	}
+	if _, err := GetCheck("search-test-bad-regexp"); err == nil {
+		t.Error("Expected the invalid check not to be registered")
+	}
+}
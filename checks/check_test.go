@@ -0,0 +1,102 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+func TestSafeRunSkipsCheckWithoutRequiredFiles(t *testing.T) {
+	dir := t.TempDir()
+	master := filepath.Join(dir, "10.0.0.1_master")
+	if err := os.Mkdir(master, 0755); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Check{Name: "skip-test", Run: okFunc, Requires: []bundle.FileTypeName{"dcos-version"}}
+	results := c.SafeRun(b)
+	if s := results.Status(); s != SSkipped {
+		t.Errorf("Expected SKIPPED without the dcos-version files, observed %v: %v", s, results)
+	}
+	version := filepath.Join(master, "opt", "mesosphere", "etc", "dcos-version.json")
+	if err := os.MkdirAll(filepath.Dir(version), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(version, []byte(`{"version": "2.1.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.SafeRun(b).Status(); s != SOK {
+		t.Errorf("Expected the check to run with the dcos-version file, observed %v", s)
+	}
+}
+
+func TestSafeRunSkipsHostsWithoutRequiredFiles(t *testing.T) {
+	dir := t.TempDir()
+	version := filepath.Join(dir, "10.0.0.1_master", "opt", "mesosphere", "etc", "dcos-version.json")
+	if err := os.MkdirAll(filepath.Dir(version), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(version, []byte(`{"version": "2.1.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "10.0.0.2_master"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder := CheckFuncBuilder{CheckMasters: func(h bundle.Host) Result {
+		var v interface{}
+		if err := h.ReadJSON("dcos-version", &v); err != nil {
+			return Result{Status: SUndefined, Value: err.Error()}
+		}
+		return Result{Status: SOK}
+	}}
+	c := Check{Name: "skip-host-test", Run: builder.MustBuild(), Requires: []bundle.FileTypeName{"dcos-version"}}
+	results := c.SafeRun(b)
+	if len(results) != 2 || results[0].Status != SOK || results[1].Status != SSkipped {
+		t.Errorf("Expected the master without dcos-version to be skipped, observed %v", results)
+	}
+	if s := results.Status(); s != SOK {
+		t.Errorf("Expected OK, observed %v", s)
+	}
+}
+
+func TestSafeRunMarksPartialErrors(t *testing.T) {
+	c := Check{Name: "partial-test", Run: func(bundle.Bundle) Results {
+		return Results{
+			{Status: SUndefined, Value: fmt.Errorf("cannot parse: %w", bundle.ErrPartialFile)},
+			{Status: SUndefined, Value: errors.New("cannot parse")},
+		}
+	}}
+	results := c.SafeRun(bundle.Bundle{})
+	if !results[0].Partial || results[1].Partial {
+		t.Errorf("Expected only the result with %v to be partial, observed %v", bundle.ErrPartialFile, results)
+	}
+}
+
+func TestResultsStatus(t *testing.T) {
+	for _, c := range []struct {
+		results  Results
+		expected Status
+	}{
+		{Results{}, SOK},
+		{Results{{Status: SSkipped}, {Status: SSkipped}}, SSkipped},
+		{Results{{Status: SSkipped}, {Status: SOK}}, SOK},
+		{Results{{Status: SSkipped}, {Status: SUndefined}}, SUndefined},
+		{Results{{Status: SSkipped}, {Status: SProblem}, {Status: SUndefined}}, SProblem},
+	} {
+		if s := c.results.Status(); s != c.expected {
+			t.Errorf("%v: expected %v, observed %v", c.results, c.expected, s)
+		}
+	}
+}
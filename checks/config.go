@@ -0,0 +1,63 @@
+package checks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// SetParam overrides the value of the parameter of the registered check.
+func SetParam(checkName string, paramName string, value string) error {
+	checkRegistryMu.RLock()
+	check, ok := checkRegistry[checkName]
+	checkRegistryMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown check %v", checkName)
+	}
+	p := check.Params.Get(paramName)
+	if p == nil {
+		return fmt.Errorf("check %v does not have parameter %v", checkName, paramName)
+	}
+	return p.Set(value)
+}
+
+// ParseSetting applies a setting in the "check.param=value" format. Check names
+// may contain dots, so the parameter name is the part after the last dot.
+func ParseSetting(s string) error {
+	eq := strings.Index(s, "=")
+	if eq < 0 {
+		return fmt.Errorf("setting %q should have the check.param=value format", s)
+	}
+	key, value := s[:eq], s[eq+1:]
+	i := strings.LastIndex(key, ".")
+	if i <= 0 || i == len(key)-1 {
+		return fmt.Errorf("setting %q should have the check.param=value format", s)
+	}
+	return SetParam(key[:i], key[i+1:], value)
+}
+
+// LoadConfig reads the check parameters from the YAML file. The file maps
+// check names to their parameters, for example:
+//
+//	marathon-deployments:
+//	  max-deployments: 40
+func LoadConfig(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var config map[string]map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("cannot parse config file %v: %v", path, err)
+	}
+	for checkName, params := range config {
+		for paramName, value := range params {
+			if err := SetParam(checkName, paramName, fmt.Sprint(value)); err != nil {
+				return fmt.Errorf("config file %v: %v", path, err)
+			}
+		}
+	}
+	return nil
+}
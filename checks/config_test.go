@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+var testParam = IntParam("max-things", 10, "max number of things")
+
+func init() {
+	MustRegisterCheck(Check{
+		Name:        "config-test.v1.0",
+		Description: "Checks config",
+		Cure:        "None",
+		Params:      Params{testParam},
+		Run: func(bundle.Bundle) Results {
+			return Results{{Status: SOK}}
+		},
+	})
+}
+
+func TestParseSetting(t *testing.T) {
+	defer testParam.Reset()
+	if err := ParseSetting("config-test.v1.0.max-things=40"); err != nil {
+		t.Fatal(err)
+	}
+	if testParam.Int() != 40 {
+		t.Errorf("Expected 40, observed %v", testParam.Int())
+	}
+	if !testParam.IsSet() {
+		t.Error("Expected the parameter to be set")
+	}
+	for _, s := range []string{
+		"config-test.v1.0.max-things",
+		"config-test.v1.0.max-things=forty",
+		"config-test.v1.0.min-things=40",
+		"no-such-check.max-things=40",
+		"max-things=40",
+	} {
+		if err := ParseSetting(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	defer testParam.Reset()
+	f, err := ioutil.TempFile("", "bun-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("config-test.v1.0:\n  max-things: 25\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadConfig(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if testParam.Int() != 25 {
+		t.Errorf("Expected 25, observed %v", testParam.Int())
+	}
+	testParam.Reset()
+	if testParam.Int() != 10 || testParam.IsSet() {
+		t.Errorf("Expected default value 10 after reset, observed %v", testParam.Int())
+	}
+}
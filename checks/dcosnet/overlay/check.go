@@ -19,12 +19,24 @@ func init() {
 		Name:        "overlay",
 		Description: "Checks if all the overlay network interfaces created successfully on all the agents",
 		Cure: "Docker already has the listed networks? Try to delete them with the `docker network rm` " +
-			"command and restart the dcos-net. See https://jira.d2iq.com/browse/COPS-4789",
+			"command and restart the dcos-net.",
 		OKSummary:      "dcos-net created all the required overlay network interfaces on all the agents",
 		ProblemSummary: "dcos-net could not create some overlay network interfaces on some agents",
-		Run:            builder.Build(),
+		Requires:       []bundle.FileTypeName{"mesos-agent-overlay"},
+		Doc: checks.Doc{
+			Background: "dcos-net creates a network interface on every agent for each overlay network. " +
+				"It fails if Docker already has a network with the same name.",
+			Symptoms: []string{"Tasks on the overlay networks of the affected agents cannot reach each other."},
+			Remediation: []string{
+				"On the affected agent, list the Docker networks:\n    docker network ls",
+				"Delete the overlay networks listed in the check results:\n    docker network rm <network>",
+				"Restart dcos-net:\n    sudo systemctl restart dcos-net",
+			},
+			Links: []checks.Link{{Title: "COPS-4789", URL: "https://jira.d2iq.com/browse/COPS-4789"}},
+		},
+		Run: builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 func collect(host bundle.Host) checks.Result {
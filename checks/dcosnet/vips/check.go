@@ -23,10 +23,23 @@ func init() {
 			"Please upgrade to 1.12.5 or later and restart the affected tasks.",
 		OKSummary:      "All VIPs have a corresponding live backends",
 		ProblemSummary: "Some VIPs do not have a corresponding live backend",
-		Run:            builder.Build(),
+		Requires:       []bundle.FileTypeName{"mesos-master-state", "vips"},
+		Doc: checks.Doc{
+			Background: "dcos-net routes the connections to a VIP to its back-ends, the IP addresses of the " +
+				"containers of the VIP tasks. The check cross-checks the VIP back-ends of every host with each " +
+				"other and with the IP addresses of the running containers from the Mesos master state.",
+			Symptoms:         []string{"Connections to a VIP fail or time out for some of its back-ends."},
+			AffectedVersions: []string{"DC/OS before 1.12.5"},
+			Remediation: []string{
+				"Upgrade DC/OS to 1.12.5 or later.",
+				"Restart the tasks of the VIPs listed in the check results.",
+			},
+			Links: []checks.Link{{Title: "MESOS-9868", URL: "https://issues.apache.org/jira/browse/MESOS-9868"}},
+		},
+		Run: builder.MustBuild(),
 	}
 
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 type containerId struct {
@@ -114,7 +127,6 @@ func collectVIPs(host bundle.Host) checks.Result {
 
 func collectMasters(host bundle.Host) checks.Result {
 	var state state
-	var faults []checks.Result
 
 	if err := host.ReadJSON("mesos-master-state", &state); err != nil {
 		return checks.Result{
@@ -123,10 +135,30 @@ func collectMasters(host bundle.Host) checks.Result {
 		}
 	}
 
+	ipMapping, faults := mapIPs(state)
+
+	ret := collectVIPs(host)
+	if ret.Status != checks.SOK {
+		return ret
+	}
+
+	vipStatus := ret.Value.(*scanResult)
+
+	return checks.Result{
+		Status: checks.SOK,
+		Value:  &scanResult{ipMapping, vipStatus.VIPs, append(faults, vipStatus.Faults...)},
+	}
+}
+
+// mapIPs maps the IP addresses of the running containers to the containers.
+// It reports the addresses which are used by more than one container.
+func mapIPs(state state) (map[string]ipMappingInfo, []checks.Result) {
+	var faults []checks.Result
 	ipMapping := make(map[string]ipMappingInfo)
-	for _, fw := range state.Frameworks {
+	for i := range state.Frameworks {
+		fw := &state.Frameworks[i]
 		for _, task := range fw.Tasks {
-			if task.State != "TASK_RUNNING" {
+			if task.State != "TASK_RUNNING" || len(task.Statuses) == 0 {
 				continue
 			}
 
@@ -146,7 +178,7 @@ func collectMasters(host bundle.Host) checks.Result {
 						})
 					} else {
 						ipMapping[addr.IPAddress] = ipMappingInfo{
-							Framework: &fw,
+							Framework: fw,
 							Container: &container,
 							AgentID:   state.AgentID,
 						}
@@ -155,18 +187,7 @@ func collectMasters(host bundle.Host) checks.Result {
 			}
 		}
 	}
-
-	ret := collectVIPs(host)
-	if ret.Status != checks.SOK {
-		return ret
-	}
-
-	vipStatus := ret.Value.(*scanResult)
-
-	return checks.Result{
-		Status: checks.SOK,
-		Value:  &scanResult{ipMapping, vipStatus.VIPs, append(faults, vipStatus.Faults...)},
-	}
+	return ipMapping, faults
 }
 
 func aggregate(r checks.Results) checks.Results {
@@ -0,0 +1,36 @@
+package unregisteredagents
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func FuzzVIPs(f *testing.F) {
+	f.Add(`{"id": "a1", "frameworks": [{"id": "f1", "tasks": [{"state": "TASK_RUNNING", "statuses": [`+
+		`{"container_status": {"container_id": {"value": "c1"}, "network_infos": [`+
+		`{"ip_addresses": [{"ip_address": "9.0.0.1"}]}]}}]}]}]}`,
+		`[{"vip": "/app:80", "backend": [{"ip": "9.0.0.1", "port": 80}]}]`)
+	f.Add(`{"frameworks": [{"tasks": [{"state": "TASK_RUNNING", "statuses": []}]}]}`, `[{"vip": "/app:80"}]`)
+	f.Fuzz(func(t *testing.T, stateJSON string, vipsJSON string) {
+		var s state
+		var vips []vip
+		if json.Unmarshal([]byte(stateJSON), &s) != nil || json.Unmarshal([]byte(vipsJSON), &vips) != nil {
+			return
+		}
+		ipMapping, faults := mapIPs(s)
+		master := checks.Result{
+			Status: checks.SOK,
+			Host:   bundle.Host{IP: bundle.ParseIP("10.0.0.1")},
+			Value:  &scanResult{ipMapping, vips, faults},
+		}
+		agent := checks.Result{
+			Status: checks.SOK,
+			Host:   bundle.Host{IP: bundle.ParseIP("10.0.0.2")},
+			Value:  &scanResult{nil, vips[:len(vips)/2], nil},
+		}
+		aggregate(checks.Results{master, agent})
+	})
+}
@@ -3,6 +3,7 @@ package cpu
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/mesosphere/bun/v2/bundle"
@@ -12,10 +13,10 @@ import (
 // Requirement values are taken from the dcos-2.0 documentation,
 // https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/.
 var (
-	cpuRequirements = map[bundle.DirType]int{
-		bundle.DTMaster:      4,
-		bundle.DTAgent:       2,
-		bundle.DTPublicAgent: 2,
+	cpuRequirements = map[bundle.DirType]*checks.Param{
+		bundle.DTMaster:      checks.IntParam("master", 4, "minimal number of CPUs on masters"),
+		bundle.DTAgent:       checks.IntParam("agent", 2, "minimal number of CPUs on agents"),
+		bundle.DTPublicAgent: checks.IntParam("public-agent", 2, "minimal number of CPUs on public agents"),
 	}
 )
 
@@ -26,15 +27,24 @@ func init() {
 		CheckPublicAgents: checkCpus,
 	}
 	check := checks.Check{
-		Name:        "cpu",
-		Description: "Checks that DC/OS nodes meet CPU requirements",
-		Cure: "Add CPUs to the problematic nodes to meet system requirements: " +
-			"https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/",
+		Name:           "cpu",
+		Description:    "Checks that DC/OS nodes meet CPU requirements",
+		Cure:           "Add CPUs to the problematic nodes to meet system requirements.",
 		OKSummary:      "All nodes meet CPU requirements",
 		ProblemSummary: "Some nodes do not meet CPU requirements",
-		Run:            builder.Build(),
+		Params: checks.Params{
+			cpuRequirements[bundle.DTMaster],
+			cpuRequirements[bundle.DTAgent],
+			cpuRequirements[bundle.DTPublicAgent],
+		},
+		Requires: []bundle.FileTypeName{"cpuinfo"},
+		Doc: checks.Doc{
+			Links: []checks.Link{{Title: "DC/OS 2.0 system requirements",
+				URL: "https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/"}},
+		},
+		Run: builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 func checkCpus(host bundle.Host) checks.Result {
@@ -47,24 +57,44 @@ func checkCpus(host bundle.Host) checks.Result {
 		}
 	}
 	defer cpuinfo.Close()
-	scanner := bufio.NewScanner(cpuinfo)
-	numCpus := 0
-	for scanner.Scan() {
-		if strings.HasPrefix(scanner.Text(), "processor\t:") {
-			numCpus++
+	numCpus, err := countCPUs(cpuinfo)
+	// A truncated cpuinfo undercounts the processors.
+	partial := bundle.Partial(cpuinfo) != nil
+	if err != nil {
+		return checks.Result{
+			Status:  checks.SUndefined,
+			Host:    host,
+			Value:   "Couldn't check. Error: " + err.Error(),
+			Partial: partial,
 		}
 	}
-	if numCpus < cpuRequirements[host.Type] {
+	required := cpuRequirements[host.Type].Int()
+	if numCpus < required {
 		return checks.Result{
 			Status: checks.SProblem,
 			Value: fmt.Sprintf(
 				"node has less than required CPUs: %.f%% (%d vs. %d)",
-				100*float64(numCpus)/float64(cpuRequirements[host.Type]),
+				100*float64(numCpus)/float64(required),
 				numCpus,
-				cpuRequirements[host.Type])}
+				required),
+			Partial: partial,
+		}
 	}
 	return checks.Result{
-		Status: checks.SOK,
-		Host:   host,
+		Status:  checks.SOK,
+		Host:    host,
+		Partial: partial,
+	}
+}
+
+// countCPUs counts the processors in the /proc/cpuinfo file.
+func countCPUs(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	numCpus := 0
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "processor\t:") {
+			numCpus++
+		}
 	}
+	return numCpus, scanner.Err()
 }
@@ -0,0 +1,34 @@
+package cpu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle/bundletest"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func FuzzCountCPUs(f *testing.F) {
+	f.Add("processor\t: 0\nvendor_id\t: GenuineIntel\n\nprocessor\t: 1\nvendor_id\t: GenuineIntel\n")
+	f.Add("")
+	f.Add("processor")
+	f.Fuzz(func(t *testing.T, cpuinfo string) {
+		n, err := countCPUs(strings.NewReader(cpuinfo))
+		if err != nil {
+			return
+		}
+		if lines := strings.Count(cpuinfo, "\n") + 1; n < 0 || n > lines {
+			t.Errorf("countCPUs(%q) = %v; the file has %v lines", cpuinfo, n, lines)
+		}
+	})
+}
+
+func TestTruncatedCPUInfoIsPartial(t *testing.T) {
+	b := bundletest.New(t)
+	b.Agent("10.0.0.2").TruncatedGzipFile("cpuinfo", "processor\t: 0\n")
+	results := bundletest.RunCheck(t, "cpu", b.Build())
+	bundletest.AssertStatus(t, results, checks.SProblem)
+	if !results.Partial() {
+		t.Errorf("Expected the results to be partial, observed %v", results)
+	}
+}
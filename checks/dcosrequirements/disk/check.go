@@ -2,7 +2,9 @@ package disk
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -14,10 +16,21 @@ import (
 // Requirement values are taken from the dcos-2.0 documentation,
 // https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/.
 var (
-	diskRequirements = map[bundle.DirType][]int{
-		bundle.DTMaster:      {120_000_000, 120_000_000},
-		bundle.DTAgent:       {60_000_000, 60_000_000},
-		bundle.DTPublicAgent: {60_000_000, 60_000_000},
+	diskRequirements = map[bundle.DirType][]*checks.Param{
+		bundle.DTMaster: {
+			checks.IntParam("master-work-dir", 120_000_000, "minimal size of the Mesos work_dir disk on masters, KB"),
+			checks.IntParam("master-runtime-dir", 120_000_000, "minimal size of the Mesos runtime_dir disk on masters, KB"),
+		},
+		bundle.DTAgent: {
+			checks.IntParam("agent-work-dir", 60_000_000, "minimal size of the Mesos work_dir disk on agents, KB"),
+			checks.IntParam("agent-runtime-dir", 60_000_000, "minimal size of the Mesos runtime_dir disk on agents, KB"),
+		},
+		bundle.DTPublicAgent: {
+			checks.IntParam("public-agent-work-dir", 60_000_000,
+				"minimal size of the Mesos work_dir disk on public agents, KB"),
+			checks.IntParam("public-agent-runtime-dir", 60_000_000,
+				"minimal size of the Mesos runtime_dir disk on public agents, KB"),
+		},
 	}
 )
 
@@ -28,15 +41,30 @@ func init() {
 		CheckPublicAgents: collect,
 	}
 	check := checks.Check{
-		Name:        "disk",
-		Description: "Checks that DC/OS nodes meet disk space requirements",
-		Cure: "Add disk space to the problematic node to meet system requirements: " +
-			"https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/",
+		Name:           "disk",
+		Description:    "Checks that DC/OS nodes meet disk space requirements",
+		Cure:           "Add disk space to the problematic node to meet system requirements.",
 		OKSummary:      "All nodes meet disk space requirements",
 		ProblemSummary: "Some nodes do not meet disk space requirements",
-		Run:            builder.Build(),
+		Params: checks.Params{
+			diskRequirements[bundle.DTMaster][0],
+			diskRequirements[bundle.DTMaster][1],
+			diskRequirements[bundle.DTAgent][0],
+			diskRequirements[bundle.DTAgent][1],
+			diskRequirements[bundle.DTPublicAgent][0],
+			diskRequirements[bundle.DTPublicAgent][1],
+		},
+		Requires: []bundle.FileTypeName{"df"},
+		Doc: checks.Doc{
+			Background: "The check finds the disks of the Mesos work_dir and runtime_dir directories " +
+				"in the df output and compares their sizes with the requirements.",
+			Files: []bundle.FileTypeName{"mesos-master-flags", "mesos-agent-flags"},
+			Links: []checks.Link{{Title: "DC/OS 2.0 system requirements",
+				URL: "https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/"}},
+		},
+		Run: builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 type disk struct {
@@ -46,12 +74,28 @@ type disk struct {
 	Mount string
 }
 
-func getDisks(host bundle.Host) ([]disk, error) {
+// getDisks returns the disks from the df file and whether the file is read
+// partially.
+func getDisks(host bundle.Host) ([]disk, bool, error) {
 	df, err := host.OpenFile("df")
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	defer df.Close()
+	disks, err := parseDF(df)
+	partial := bundle.Partial(df) != nil
+	if err != nil {
+		return nil, partial, fmt.Errorf("cannot parse %v: %w", df.Name(), err)
 	}
-	scanner := bufio.NewScanner(df)
+	return disks, partial, nil
+}
+
+// parseDF parses the output of df, e.g.
+//
+//	Filesystem     1K-blocks    Used Available Use% Mounted on
+//	/dev/xvda1      52416492 8155440  44261052  16% /
+func parseDF(r io.Reader) ([]disk, error) {
+	scanner := bufio.NewScanner(r)
 	var disks []disk
 	headerParsed := false
 	for scanner.Scan() {
@@ -60,6 +104,12 @@ func getDisks(host bundle.Host) ([]disk, error) {
 			continue
 		}
 		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("unexpected line: %q", scanner.Text())
+		}
 		size, err := strconv.Atoi(fields[1])
 		if err != nil {
 			return nil, err
@@ -73,9 +123,12 @@ func getDisks(host bundle.Host) ([]disk, error) {
 				ID:    fields[0],
 				Size:  size,
 				Used:  used,
-				Mount: fields[5],
+				Mount: strings.Join(fields[5:], " "),
 			})
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 	return disks, nil
 }
 
@@ -87,9 +140,9 @@ func getMountpoint(dir string, disks []disk) (disk, error) {
 		if err != nil {
 			return candidate, err
 		}
-		if !strings.HasPrefix(rel, ".") && !strings.HasPrefix(rel, "/") {
-			if found &&
-				len(filepath.SplitList(rel)) <= len(filepath.SplitList(candidate.Mount)) {
+		if rel != ".." && !strings.HasPrefix(rel, "../") {
+			// The deepest mount point wins, e.g. /var/lib/mesos over /.
+			if found && len(filepath.Clean(d.Mount)) <= len(filepath.Clean(candidate.Mount)) {
 				continue
 			}
 			candidate = d
@@ -111,14 +164,15 @@ func checkWorkDir(host bundle.Host, disks []disk, dir string) checks.Result {
 			Value:  "Couldn't get Mountpoint wile checking work disk requirement: " + err.Error(),
 		}
 	}
-	if workDirDisk.Size < diskRequirements[host.Type][0] {
+	required := diskRequirements[host.Type][0].Int()
+	if workDirDisk.Size < required {
 		return checks.Result{
 			Status: checks.SProblem,
 			Host:   host,
 			Value: fmt.Sprintf("node has less than required disk for Mesos 'work_dir': %.f%% (%.2f GB vs. %.2f GB)",
-				100*float64(workDirDisk.Size)/float64(diskRequirements[host.Type][0]),
+				100*float64(workDirDisk.Size)/float64(required),
 				convertKBtoGB(workDirDisk.Size),
-				convertKBtoGB(diskRequirements[host.Type][0])),
+				convertKBtoGB(required)),
 		}
 	}
 	return checks.Result{
@@ -136,14 +190,15 @@ func checkRuntimeDir(host bundle.Host, disks []disk, dir string) checks.Result {
 			Value:  "Couldn't get Mountpoint wile checking runtime disk requirement: " + err.Error(),
 		}
 	}
-	if runtimeDirDisk.Size < diskRequirements[host.Type][1] {
+	required := diskRequirements[host.Type][1].Int()
+	if runtimeDirDisk.Size < required {
 		return checks.Result{
 			Status: checks.SProblem,
 			Host:   host,
 			Value: fmt.Sprintf("node has less than required disk for Mesos 'runtime_dir': %.f%% (%.2f GB vs. %.2f GB)",
-				100*float64(runtimeDirDisk.Size)/float64(diskRequirements[host.Type][1]),
+				100*float64(runtimeDirDisk.Size)/float64(required),
 				convertKBtoGB(runtimeDirDisk.Size),
-				convertKBtoGB(diskRequirements[host.Type][1])),
+				convertKBtoGB(required)),
 		}
 	}
 	return checks.Result{
@@ -161,12 +216,13 @@ func collect(host bundle.Host) checks.Result {
 		Flags Flags `json:"flags"`
 	}
 
-	disks, err := getDisks(host)
+	disks, partial, err := getDisks(host)
 	if err != nil {
 		return checks.Result{
-			Status: checks.SUndefined,
-			Host:   host,
-			Value:  "Couldn't check disk requirement: " + err.Error(),
+			Status:  checks.SUndefined,
+			Host:    host,
+			Value:   "Couldn't check disk requirement: " + err.Error(),
+			Partial: partial,
 		}
 	}
 	flagsFile := map[bundle.DirType]bundle.FileTypeName{
@@ -177,9 +233,10 @@ func collect(host bundle.Host) checks.Result {
 	var flags MesosFlags
 	if err = host.ReadJSON(flagsFile[host.Type], &flags); err != nil {
 		return checks.Result{
-			Status: checks.SUndefined,
-			Host:   host,
-			Value:  "Couldn't read JSON while checking disk requirement: " + err.Error(),
+			Status:  checks.SUndefined,
+			Host:    host,
+			Value:   "Couldn't read JSON while checking disk requirement: " + err.Error(),
+			Partial: partial || errors.Is(err, bundle.ErrPartialFile),
 		}
 	}
 	var results checks.Results
@@ -188,8 +245,9 @@ func collect(host bundle.Host) checks.Result {
 		results = append(results, checkRuntimeDir(host, disks, flags.Flags.RuntimeDir))
 	}
 	result := checks.Result{
-		Host:   host,
-		Status: results.Status(),
+		Host:    host,
+		Status:  results.Status(),
+		Partial: partial,
 	}
 	if result.Status == checks.SOK {
 		return result
@@ -201,9 +259,8 @@ func collect(host bundle.Host) checks.Result {
 	for _, u := range results.Undefined() {
 		messages = append(messages, u.Value.(string))
 	}
-	return checks.Result{
-		Value: strings.Join(messages, "\n"),
-	}
+	result.Value = strings.Join(messages, "\n")
+	return result
 }
 
 func convertKBtoGB(kb int) float64 {
@@ -0,0 +1,68 @@
+package disk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle/bundletest"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+const df = `Filesystem     1K-blocks    Used Available Use% Mounted on
+/dev/xvda1      52416492 8155440  44261052  16% /
+/dev/xvdb      125829120 1048576 124780544   1% /var/lib/mesos
+`
+
+func TestParseDF(t *testing.T) {
+	disks, err := parseDF(strings.NewReader(df))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(disks) != 2 {
+		t.Fatalf("expected 2 disks, observed %+v", disks)
+	}
+	d, err := getMountpoint("/var/lib/mesos/slave", disks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ID != "/dev/xvdb" || d.Size != 125829120 {
+		t.Errorf("expected /dev/xvdb of 125829120 KB, observed %+v", d)
+	}
+	for _, s := range []string{"header\n/dev/xvda1 52416492\n", "header\n/dev/xvda1 big 1 1 1% /\n"} {
+		if _, err := parseDF(strings.NewReader(s)); err == nil {
+			t.Errorf("parseDF(%q): expected an error", s)
+		}
+	}
+}
+
+func FuzzParseDF(f *testing.F) {
+	f.Add(df, "/var/lib/mesos")
+	f.Add("header\n/dev/xvda1\n", "/")
+	f.Fuzz(func(t *testing.T, output string, dir string) {
+		disks, err := parseDF(strings.NewReader(output))
+		if err != nil {
+			return
+		}
+		for _, d := range disks {
+			if d.ID == "" || d.Mount == "" {
+				t.Errorf("parseDF(%q): disk without a device or a mount point: %+v", output, d)
+			}
+		}
+		_, _ = getMountpoint(dir, disks)
+	})
+}
+
+func TestDiskRequirements(t *testing.T) {
+	b := bundletest.New(t)
+	b.Agent("10.0.0.2").
+		File("df", df).
+		File("mesos-agent-flags", `{"flags": {"work_dir": "/var/lib/mesos/slave"}}`)
+	small := "Filesystem 1K-blocks Used Available Use% Mounted on\n/dev/xvdb 1000000 1000 999000 1% /var/lib/mesos\n"
+	b.Agent("10.0.0.3").
+		File("df", small).
+		File("mesos-agent-flags", `{"flags": {"work_dir": "/var/lib/mesos/slave"}}`)
+	results := bundletest.RunCheck(t, "disk", b.Build())
+	bundletest.AssertHostStatus(t, results, "10.0.0.2", checks.SOK)
+	bundletest.AssertHostStatus(t, results, "10.0.0.3", checks.SProblem)
+	bundletest.AssertStatus(t, results, checks.SProblem)
+}
@@ -2,7 +2,9 @@ package mem
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -13,10 +15,10 @@ import (
 // Requirement values are taken from the dcos-2.0 documentation,
 // https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/.
 var (
-	memRequirements = map[bundle.DirType]int{
-		bundle.DTMaster:      32_000_000,
-		bundle.DTAgent:       16_000_000,
-		bundle.DTPublicAgent: 16_000_000,
+	memRequirements = map[bundle.DirType]*checks.Param{
+		bundle.DTMaster:      checks.IntParam("master", 32_000_000, "minimal memory on masters, KB"),
+		bundle.DTAgent:       checks.IntParam("agent", 16_000_000, "minimal memory on agents, KB"),
+		bundle.DTPublicAgent: checks.IntParam("public-agent", 16_000_000, "minimal memory on public agents, KB"),
 	}
 )
 
@@ -27,15 +29,24 @@ func init() {
 		CheckPublicAgents: checkMem,
 	}
 	check := checks.Check{
-		Name:        "mem",
-		Description: "Checks that DC/OS nodes meet memory requirements",
-		Cure: "Add memory to the problematic nodes to meet system requirements: " +
-			"https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/`",
+		Name:           "mem",
+		Description:    "Checks that DC/OS nodes meet memory requirements",
+		Cure:           "Add memory to the problematic nodes to meet system requirements.",
 		OKSummary:      "All nodes meet memory requirements",
 		ProblemSummary: "Some nodes do not meet memory requirements",
-		Run:            builder.Build(),
+		Params: checks.Params{
+			memRequirements[bundle.DTMaster],
+			memRequirements[bundle.DTAgent],
+			memRequirements[bundle.DTPublicAgent],
+		},
+		Requires: []bundle.FileTypeName{"meminfo"},
+		Doc: checks.Doc{
+			Links: []checks.Link{{Title: "DC/OS 2.0 system requirements",
+				URL: "https://docs.d2iq.com/mesosphere/dcos/2.0/installing/production/system-requirements/"}},
+		},
+		Run: builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 func checkMem(host bundle.Host) checks.Result {
@@ -48,42 +59,61 @@ func checkMem(host bundle.Host) checks.Result {
 		}
 	}
 	defer meminfo.Close()
-	scanner := bufio.NewScanner(meminfo)
+	mem, err := parseMemTotal(meminfo)
+	partial := bundle.Partial(meminfo) != nil
+	if err != nil {
+		return checks.Result{
+			Status:  checks.SUndefined,
+			Host:    host,
+			Value:   fmt.Sprintf("Couldn't check. Error: cannot parse %v: %v", meminfo.Name(), err),
+			Partial: partial,
+		}
+	}
+	required := memRequirements[host.Type].Int()
+	if mem < required {
+		return checks.Result{
+			Status: checks.SProblem,
+			Host:   host,
+			Value: fmt.Sprintf(
+				"node has less than required memory: %.f%% (%.2f GB vs. %.2f GB)",
+				100*float64(mem)/float64(required),
+				convertKBtoGB(mem),
+				convertKBtoGB(required)),
+			Partial: partial,
+		}
+	}
+	return checks.Result{
+		Status:  checks.SOK,
+		Host:    host,
+		Partial: partial,
+	}
+}
+
+// parseMemTotal returns the total memory in KB from the /proc/meminfo file,
+// e.g. from the line "MemTotal:       32780000 kB".
+func parseMemTotal(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		if !strings.HasPrefix(scanner.Text(), "MemTotal") {
 			continue
 		}
-		tokens := strings.Split(scanner.Text(), " ")
-		mem, err := strconv.Atoi(tokens[len(tokens)-2])
-		if err != nil {
-			return checks.Result{
-				Status: checks.SUndefined,
-				Host:   host,
-				Value:  "Couldn't check. Error: " + err.Error(),
-			}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[2] != "kB" {
+			return 0, fmt.Errorf("unexpected MemTotal line: %q", scanner.Text())
 		}
-		if mem < memRequirements[host.Type] {
-			return checks.Result{
-				Status: checks.SProblem,
-				Host:   host,
-				Value: fmt.Sprintf(
-					"node has less than required memory: %.f%% (%.2f GB vs. %.2f GB)",
-					100*float64(mem)/float64(memRequirements[host.Type]),
-					convertKBtoGB(mem),
-					convertKBtoGB(memRequirements[host.Type])),
-			}
+		mem, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0, err
 		}
-		return checks.Result{
-			Status: checks.SOK,
-			Host:   host,
+		if mem < 0 {
+			return 0, fmt.Errorf("negative MemTotal: %v", mem)
 		}
+		return mem, nil
 	}
-	return checks.Result{
-		Status: checks.SUndefined,
-		Host:   host,
-		Value: "Couldn't find MemTotal in one the following files: " +
-			strings.Join(bundle.GetFileType("meminfo").Paths, ", "),
+	if err := scanner.Err(); err != nil {
+		return 0, err
 	}
+	return 0, errors.New("MemTotal not found")
 }
 
 func convertKBtoGB(kb int) float64 {
@@ -0,0 +1,36 @@
+package mem
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMemTotal(t *testing.T) {
+	for _, c := range []struct {
+		meminfo string
+		mem     int
+		ok      bool
+	}{
+		{"MemTotal:       32780000 kB\nMemFree:         1000000 kB\n", 32780000, true},
+		{"MemFree:         1000000 kB\n", 0, false},
+		{"MemTotal:\n", 0, false},
+		{"MemTotal: lots kB\n", 0, false},
+		{"MemTotal: -1 kB\n", 0, false},
+	} {
+		mem, err := parseMemTotal(strings.NewReader(c.meminfo))
+		if mem != c.mem || (err == nil) != c.ok {
+			t.Errorf("parseMemTotal(%q): expected %v, %v; observed %v, %v", c.meminfo, c.mem, c.ok, mem, err)
+		}
+	}
+}
+
+func FuzzParseMemTotal(f *testing.F) {
+	f.Add("MemTotal:       32780000 kB\nMemFree:         1000000 kB\n")
+	f.Add("MemTotal\n")
+	f.Fuzz(func(t *testing.T, meminfo string) {
+		mem, err := parseMemTotal(strings.NewReader(meminfo))
+		if err == nil && mem < 0 {
+			t.Errorf("parseMemTotal(%q) = %v", meminfo, mem)
+		}
+	})
+}
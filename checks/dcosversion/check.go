@@ -19,9 +19,10 @@ func init() {
 		Cure:           "Upgrade the nodes which have older DC/OS versions.",
 		OKSummary:      "All the nodes have the same DC/OS version.",
 		ProblemSummary: "The nodes have different DC/OS versions installed.",
-		Run:            builder.Build(),
+		Requires:       []bundle.FileTypeName{"dcos-version"},
+		Run:            builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 // Version represents the dcos-version JSON file
@@ -33,13 +34,15 @@ func collect(host bundle.Host) checks.Result {
 	v := Version{}
 	if err := host.ReadJSON("dcos-version", &v); err != nil {
 		return checks.Result{
-			Status: checks.SUndefined,
-			Value:  err,
+			Status:   checks.SUndefined,
+			Value:    err,
+			Evidence: []bundle.FileTypeName{"dcos-version"},
 		}
 	}
 	return checks.Result{
-		Status: checks.SOK,
-		Value:  v.Version,
+		Status:   checks.SOK,
+		Value:    v.Version,
+		Evidence: []bundle.FileTypeName{"dcos-version"},
 	}
 }
 
@@ -0,0 +1,77 @@
+package checks
+
+import (
+	"fmt"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+// Doc documents a check in more detail than its description and cure, so
+// bun explain can guide the user through the problem. All the fields are
+// optional.
+type Doc struct {
+	// Background explains what the check detects and why it matters.
+	Background string `yaml:"background" json:"background,omitempty"`
+	// Symptoms are the signs of the problem which can be observed in the cluster.
+	Symptoms []string `yaml:"symptoms" json:"symptoms,omitempty"`
+	// Files are the types of the files the check inspects in addition to
+	// the required ones; see Check.Requires.
+	Files []bundle.FileTypeName `yaml:"files" json:"files,omitempty"`
+	// AffectedVersions are the versions of DC/OS or its components which
+	// have the problem, e.g. "DC/OS 1.12.0 - 1.12.4".
+	AffectedVersions []string `yaml:"affectedVersions" json:"affectedVersions,omitempty"`
+	// Remediation are the steps to fix the problem in the order they should be
+	// taken; a step may contain shell commands.
+	Remediation []string `yaml:"remediation" json:"remediation,omitempty"`
+	// Links are the KB articles, JIRA issues, and other references.
+	Links []Link `yaml:"links" json:"links,omitempty"`
+}
+
+// IsEmpty returns true if none of the fields is set.
+func (d Doc) IsEmpty() bool {
+	return d.Background == "" && len(d.Symptoms) == 0 && len(d.Files) == 0 &&
+		len(d.AffectedVersions) == 0 && len(d.Remediation) == 0 && len(d.Links) == 0
+}
+
+// Link is a reference to a document about the problem.
+type Link struct {
+	Title string `yaml:"title" json:"title,omitempty"`
+	URL   string `yaml:"url" json:"url"`
+}
+
+func (l Link) String() string {
+	if l.Title == "" {
+		return l.URL
+	}
+	return l.Title + ": " + l.URL
+}
+
+// Files returns the types of the files the check inspects: the required
+// ones and the ones from the documentation.
+func (c Check) Files() []bundle.FileTypeName {
+	files := make([]bundle.FileTypeName, 0, len(c.Requires)+len(c.Doc.Files))
+	seen := make(map[bundle.FileTypeName]bool)
+	for _, types := range [][]bundle.FileTypeName{c.Requires, c.Doc.Files} {
+		for _, t := range types {
+			if !seen[t] {
+				seen[t] = true
+				files = append(files, t)
+			}
+		}
+	}
+	return files
+}
+
+func (d Doc) validate() error {
+	for _, t := range d.Files {
+		if _, err := bundle.GetFileType(t); err != nil {
+			return err
+		}
+	}
+	for _, l := range d.Links {
+		if l.URL == "" {
+			return fmt.Errorf("link %q has no URL", l.Title)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrUnknownCheck is returned when a check is not in the registry.
+	ErrUnknownCheck = errors.New("unknown check")
+	// ErrDuplicateCheck is returned when a check is registered twice.
+	ErrDuplicateCheck = errors.New("duplicate check")
+	// ErrInvalidCheck is returned when a check description is not valid.
+	ErrInvalidCheck = errors.New("invalid check")
+	// ErrNoCheckHostFunc is returned by the CheckFuncBuilder when none of
+	// the CheckHostFunc functions is specified.
+	ErrNoCheckHostFunc = errors.New("at least one of the CheckHostFunc functions should be specified")
+)
+
+// Errors is a list of errors, e.g. errors of several checks registration.
+type Errors []error
+
+func (e Errors) Error() string {
+	s := make([]string, 0, len(e))
+	for _, err := range e {
+		s = append(s, err.Error())
+	}
+	return strings.Join(s, "\n")
+}
+
+// Is reports whether any of the errors matches the target.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// PanicError describes a check which panicked. It is used as a value of
+// the UNDEFINED result.
+type PanicError struct {
+	Check string
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("check %v crashed: %v", e.Check, e.Value)
+}
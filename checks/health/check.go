@@ -17,9 +17,10 @@ func init() {
 		Cure:           "Check the logs of the unhealthy component.",
 		OKSummary:      "All components are healthy.",
 		ProblemSummary: "Found unhealthy components.",
-		Run:            builder.Build(),
+		Requires:       []bundle.FileTypeName{"diagnostics-health"},
+		Run:            builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 // Host represents the "host" object in the health JSON file
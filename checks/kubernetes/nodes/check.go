@@ -0,0 +1,87 @@
+package nodes
+
+import (
+	"fmt"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func init() {
+	check := checks.Check{
+		Name:           "kubernetes-node-not-ready",
+		Description:    "Checks if all the Kubernetes nodes are Ready",
+		Cure:           "Check the kubelet and containerd journals of the nodes which are not Ready.",
+		OKSummary:      "All the Kubernetes nodes are Ready.",
+		ProblemSummary: "Some Kubernetes nodes are not Ready.",
+		Flavor:         bundle.FKubernetes,
+		Requires:       []bundle.FileTypeName{"kubernetes-nodes"},
+		Doc: checks.Doc{
+			Background: "The kubelet of each node reports the node condition to the API server. A node which is " +
+				"not Ready doesn't get new pods, and its pods are evicted after a timeout.",
+			Symptoms: []string{"Pods are Pending or Terminating for a long time."},
+			Files:    []bundle.FileTypeName{"kubelet", "containerd"},
+			Remediation: []string{
+				"See the conditions of the node:\n    kubectl describe node <node>",
+				"Check the kubelet and containerd journals of the node:\n    journalctl -u kubelet\n    journalctl -u containerd",
+			},
+		},
+		Run: checkFunc,
+	}
+	checks.MustRegisterCheck(check)
+}
+
+type nodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+		Status struct {
+			Conditions []struct {
+				Type    string `yaml:"type"`
+				Status  string `yaml:"status"`
+				Reason  string `yaml:"reason"`
+				Message string `yaml:"message"`
+			} `yaml:"conditions"`
+		} `yaml:"status"`
+	} `yaml:"items"`
+}
+
+func checkFunc(b bundle.Bundle) checks.Results {
+	var nodes nodeList
+	if err := b.ReadYAML("kubernetes-nodes", &nodes); err != nil {
+		return checks.Results{{
+			Status: checks.SUndefined,
+			Value:  err,
+		}}
+	}
+	results := make(checks.Results, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		result := checks.Result{
+			Status:   checks.SProblem,
+			Value:    fmt.Sprintf("Node %v has no Ready condition.", node.Metadata.Name),
+			Evidence: []bundle.FileTypeName{"kubelet"},
+		}
+		for _, h := range b.Hosts {
+			if h.Matches(node.Metadata.Name) {
+				result.Host = h
+				break
+			}
+		}
+		for _, c := range node.Status.Conditions {
+			if c.Type != "Ready" {
+				continue
+			}
+			if c.Status == "True" {
+				result.Status = checks.SOK
+				result.Value = fmt.Sprintf("Node %v is Ready.", node.Metadata.Name)
+				break
+			}
+			result.Value = fmt.Sprintf("Node %v is not Ready (%v): %v %v",
+				node.Metadata.Name, c.Status, c.Reason, c.Message)
+			break
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,27 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func TestNodeNotReady(t *testing.T) {
+	b, err := bundle.New("test_bundles/cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := checks.GetCheck("kubernetes-node-not-ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := c.Run(b)
+	if len(results.OKs()) != 1 || len(results.Problems()) != 1 {
+		t.Fatalf("Expected 1 OK and 1 problem, observed %v", results)
+	}
+	problem := results.Problems()[0]
+	if problem.Host.IP.Hostname != "worker-1" || problem.Host.Type != bundle.DTWorker {
+		t.Errorf("Expected the problem on worker-1, observed %+v", problem.Host)
+	}
+}
@@ -0,0 +1,97 @@
+package pods
+
+import (
+	"fmt"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func init() {
+	check := checks.Check{
+		Name:           "kubernetes-crashloopbackoff",
+		Description:    "Checks if there are Kubernetes pods with containers in the CrashLoopBackOff state",
+		Cure:           "Check the logs of the crashing containers in the pod-logs directory and the events of the pods.",
+		OKSummary:      "No containers are in the CrashLoopBackOff state.",
+		ProblemSummary: "Some containers are in the CrashLoopBackOff state.",
+		Flavor:         bundle.FKubernetes,
+		Requires:       []bundle.FileTypeName{"kubernetes-pods"},
+		Doc: checks.Doc{
+			Background: "Kubernetes restarts a crashing container with an exponential back-off delay; the container " +
+				"is in the CrashLoopBackOff state while it waits for the next restart.",
+			Files: []bundle.FileTypeName{"kubernetes-pod-logs", "kubernetes-events"},
+			Remediation: []string{
+				"Read the log of the previous run of the container:\n    kubectl logs --previous -n <namespace> <pod> -c <container>",
+				"See the events of the pod:\n    kubectl describe pod -n <namespace> <pod>",
+			},
+		},
+		Run: checkFunc,
+	}
+	checks.MustRegisterCheck(check)
+}
+
+type containerStatus struct {
+	Name         string `yaml:"name"`
+	RestartCount int    `yaml:"restartCount"`
+	State        struct {
+		Waiting struct {
+			Reason  string `yaml:"reason"`
+			Message string `yaml:"message"`
+		} `yaml:"waiting"`
+	} `yaml:"state"`
+}
+
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `yaml:"name"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"metadata"`
+		Spec struct {
+			NodeName string `yaml:"nodeName"`
+		} `yaml:"spec"`
+		Status struct {
+			InitContainerStatuses []containerStatus `yaml:"initContainerStatuses"`
+			ContainerStatuses     []containerStatus `yaml:"containerStatuses"`
+		} `yaml:"status"`
+	} `yaml:"items"`
+}
+
+func checkFunc(b bundle.Bundle) checks.Results {
+	var pods podList
+	if err := b.ReadYAML("kubernetes-pods", &pods); err != nil {
+		return checks.Results{{
+			Status: checks.SUndefined,
+			Value:  err,
+		}}
+	}
+	var results checks.Results
+	for _, pod := range pods.Items {
+		statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+		for _, s := range statuses {
+			if s.State.Waiting.Reason != "CrashLoopBackOff" {
+				continue
+			}
+			result := checks.Result{
+				Status: checks.SProblem,
+				Value: fmt.Sprintf("Container %v of pod %v/%v restarted %v time(s): %v",
+					s.Name, pod.Metadata.Namespace, pod.Metadata.Name, s.RestartCount, s.State.Waiting.Message),
+				Evidence: []bundle.FileTypeName{"kubelet"},
+			}
+			for _, h := range b.Hosts {
+				if pod.Spec.NodeName != "" && h.Matches(pod.Spec.NodeName) {
+					result.Host = h
+					break
+				}
+			}
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 {
+		return checks.Results{{
+			Status: checks.SOK,
+			Value:  fmt.Sprintf("%v pod(s) checked.", len(pods.Items)),
+		}}
+	}
+	return results
+}
@@ -0,0 +1,30 @@
+package pods
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func TestCrashLoopBackOff(t *testing.T) {
+	b, err := bundle.New("test_bundles/cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := checks.GetCheck("kubernetes-crashloopbackoff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := c.Run(b)
+	if len(results) != 1 || results[0].Status != checks.SProblem {
+		t.Fatalf("Expected 1 problem, observed %v", results)
+	}
+	if v := results[0].Value.(string); !strings.Contains(v, "shop/api-7d4b9c8f5-x2x9q") {
+		t.Errorf("Expected the crashing pod in the value, observed %v", v)
+	}
+	if results[0].Host.IP.Hostname != "worker-1" {
+		t.Errorf("Expected the problem on worker-1, observed %+v", results[0].Host)
+	}
+}
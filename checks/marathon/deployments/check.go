@@ -1,23 +1,28 @@
 package deployments
 
 import (
+	"fmt"
+
 	"github.com/mesosphere/bun/v2/bundle"
 	"github.com/mesosphere/bun/v2/checks"
 )
 
 // max number of Marathon deployments considered healthy
-const maxDeployments = 10
+var maxDeployments = checks.IntParam("max-deployments", 10,
+	"max number of Marathon deployments considered healthy")
 
 func init() {
 	check := checks.Check{
 		Name:           "marathon-deployments",
 		Description:    "Checks if Marathon has too many deployments",
 		Cure:           "Too many deployments can mean that the cluster lost resources during some incident.",
-		OKSummary:      "Marathon has less than 10 deployments.",
-		ProblemSummary: "Marathon has more than 10 deployments.",
+		OKSummary:      "Marathon doesn't have too many deployments.",
+		ProblemSummary: "Marathon has too many deployments.",
+		Params:         checks.Params{maxDeployments},
+		Requires:       []bundle.FileTypeName{"marathon-deployments"},
 		Run:            checkFunc,
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 func checkFunc(b bundle.Bundle) checks.Results {
@@ -38,18 +43,20 @@ func checkFunc(b bundle.Bundle) checks.Results {
 			},
 		}
 	}
-	if len(deployments) > maxDeployments {
+	value := fmt.Sprintf("Marathon has %v deployments, the maximum considered healthy is %v (%v)",
+		len(deployments), maxDeployments.Int(), maxDeployments.Name)
+	if len(deployments) > maxDeployments.Int() {
 		return checks.Results{
 			checks.Result{
 				Status: checks.SProblem,
-				Value:  len(deployments),
+				Value:  value,
 			},
 		}
 	}
 	return checks.Results{
 		checks.Result{
 			Status: checks.SOK,
-			Value:  len(deployments),
+			Value:  value,
 		},
 	}
 }
@@ -0,0 +1,29 @@
+package deployments
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle/bundletest"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func TestDeployments(t *testing.T) {
+	b := bundletest.New(t)
+	b.Master("10.0.0.1").File("marathon-deployments", `[{"id": "1"}, {"id": "2"}]`)
+	results := bundletest.RunCheck(t, "marathon-deployments", b.Build())
+	bundletest.AssertStatus(t, results, checks.SOK)
+}
+
+func TestTooManyDeployments(t *testing.T) {
+	deployments := make([]struct{}, maxDeployments.Int()+1)
+	b := bundletest.New(t)
+	b.Master("10.0.0.1").JSONFile("marathon-deployments", deployments)
+	results := bundletest.RunCheck(t, "marathon-deployments", b.Build())
+	bundletest.AssertCounts(t, results, 0, 1, 0)
+	expected := fmt.Sprintf("the maximum considered healthy is %v", maxDeployments.Int())
+	if !strings.Contains(results[0].Value.(string), expected) {
+		t.Errorf("Expected the value to mention the limit, observed %v", results[0].Value)
+	}
+}
@@ -17,9 +17,10 @@ func init() {
 			"Check if the application's tasks are failing and read through their logs.",
 		OKSummary:      "Some Marathon apps have less or more instances than required.",
 		ProblemSummary: "All Marathon tasks have the required amount of instances.",
+		Requires:       []bundle.FileTypeName{"marathon-apps"},
 		Run:            checkFunc,
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 type apps struct {
@@ -11,15 +11,26 @@ func init() {
 	check := checks.Check{
 		Name: "marathon-lb-1.14.1",
 		Description: "Detects if Marathon-LB v1.14.1 is installed. " +
-			"This Marathon-LB version is affected by a HAProxy bug: https://github.com/haproxy/haproxy/issues/278",
-		Cure: "Upgrade to the latest Marathon-LB version. Please see " +
-			"https://support.d2iq.com/s/article/Known-Issue-L4TOUT-on-Marathon-LB-1-14-1-Shipped-with-HAProxy-2-0-6 " +
-			"for more details.",
+			"This Marathon-LB version is affected by a HAProxy bug.",
+		Cure:           "Upgrade to the latest Marathon-LB version.",
 		OKSummary:      "No Marathon-LB v1.14.1 installed",
 		ProblemSummary: "Marathon-LB v1.14.1 is installed",
-		Run:            checkFunc,
+		Requires:       []bundle.FileTypeName{"marathon-apps"},
+		Doc: checks.Doc{
+			Background: "Marathon-LB v1.14.1 ships with HAProxy 2.0.6, which has a bug in the health checks of " +
+				"the back-ends. The check looks for the Marathon-LB v1.14.1 image in the Marathon apps.",
+			Symptoms:         []string{"HAProxy marks healthy back-ends as down with the L4TOUT status."},
+			AffectedVersions: []string{"Marathon-LB 1.14.1"},
+			Remediation:      []string{"Upgrade Marathon-LB to the latest version."},
+			Links: []checks.Link{
+				{Title: "HAProxy issue", URL: "https://github.com/haproxy/haproxy/issues/278"},
+				{Title: "Known issue: L4TOUT on Marathon-LB 1.14.1", URL: "https://support.d2iq.com/s/article/" +
+					"Known-Issue-L4TOUT-on-Marathon-LB-1-14-1-Shipped-with-HAProxy-2-0-6"},
+			},
+		},
+		Run: checkFunc,
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 type marathonApps struct {
@@ -9,7 +9,8 @@ import (
 
 // number of events in an actor's mailbox after which the actor is
 // considered backlogged
-const maxEvents = 30
+var maxEvents = checks.IntParam("max-events", 30,
+	"number of events in an actor's mailbox after which the actor is considered backlogged")
 
 func init() {
 	builder := checks.CheckFuncBuilder{
@@ -25,9 +26,11 @@ func init() {
 			" with API calls.",
 		OKSummary:      "All Mesos actors are fine.",
 		ProblemSummary: "Some Mesos actors are backlogged.",
-		Run:            builder.Build(),
+		Params:         checks.Params{maxEvents},
+		Requires:       []bundle.FileTypeName{"mesos-processes"},
+		Run:            builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 type MesosActor struct {
@@ -45,9 +48,9 @@ func collect(host bundle.Host) checks.Result {
 	}
 	var mailboxes []string
 	for _, a := range actors {
-		if len(a.Events) > maxEvents {
+		if len(a.Events) > maxEvents.Int() {
 			mailboxes = append(mailboxes, fmt.Sprintf("(Mesos) %v@%v: mailbox size = %v (> %v)",
-				a.ID, host.IP, len(a.Events), maxEvents))
+				a.ID, host.IP, len(a.Events), maxEvents.Int()))
 		}
 	}
 	if len(mailboxes) > 0 {
@@ -17,13 +17,25 @@ func init() {
 		Name: "mesos-containerizer-debug",
 		Description: "Checks if the Mesos containerizer (UCR) has become unresponsive due to hanging synchronous syscalls",
 		Cure: "Check that no Mesos-related OS processes stuck in a D-state on Mesos agents. Otherwise, this is a false positive alert. " +
-			"Mesos containerizer (UCR) may become unresponsive due to a kernel bug such as kmem leak described in https://support.d2iq.com/s/article/Known-Issue-KMEM-with-Kubernetes-MSPH-2019-0002. " +
+			"Mesos containerizer (UCR) may become unresponsive due to a kernel bug such as kmem leak. " +
 			"If the problem persists, reboot the agent node.",
 		OKSummary:      "All Mesos agents are fine.",
 		ProblemSummary: "Some Mesos agents may be stuck due to hanging Mesos containerizer processes.",
-		Run:            builder.Build(),
+		Requires:       []bundle.FileTypeName{"mesos-agent-containerizer-debug"},
+		Doc: checks.Doc{
+			Background: "The Mesos containerizer performs some operations, e.g. mounts, with synchronous syscalls. " +
+				"If a syscall hangs, the pending operations pile up and the containerizer stops launching and destroying containers.",
+			Symptoms: []string{"Tasks on the agent are stuck in the staging or killing state."},
+			Remediation: []string{
+				"Find the processes in the uninterruptible sleep (D) state on the agent:\n    ps -eo pid,stat,cmd | awk '$2 ~ /D/'",
+				"If the problem persists, reboot the agent node.",
+			},
+			Links: []checks.Link{{Title: "Known issue: KMEM with Kubernetes (MSPH-2019-0002)",
+				URL: "https://support.d2iq.com/s/article/Known-Issue-KMEM-with-Kubernetes-MSPH-2019-0002"}},
+		},
+		Run:            builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 type PendingOperations struct {
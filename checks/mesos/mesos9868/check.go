@@ -62,12 +62,28 @@ func init() {
 	check := checks.Check{
 		Name:           "mesos-9868",
 		Description:    "Checks if the cluster is affected by the MESOS-9868 bug",
-		Cure:           "Please, see https://issues.apache.org/jira/browse/MESOS-9868 for results.",
+		Cure:           "Upgrade DC/OS to 1.12.5 or later and restart the affected tasks.",
 		OKSummary:      "The cluster is not affected by the MESOS-9868 bug.",
 		ProblemSummary: "The cluster is affected by the MESOS-9868 bug.",
-		Run:            builder.Build(),
+		Requires:       []bundle.FileTypeName{"mesos-master-state", "mesos-agent-containers"},
+		Doc: checks.Doc{
+			Background: "Because of the MESOS-9868 bug, the IP addresses of a task reported by the Mesos master " +
+				"may differ from the IP addresses of its container reported by the Mesos agent. The check " +
+				"compares the addresses from the master state with the ones from the agent containers endpoints.",
+			Symptoms: []string{
+				"Tasks are unreachable by the IP addresses shown in the Mesos UI or DNS.",
+				"VIPs route connections to IP addresses which no container has.",
+			},
+			AffectedVersions: []string{"DC/OS before 1.12.5"},
+			Remediation: []string{
+				"Upgrade DC/OS to 1.12.5 or later.",
+				"Restart the tasks of the containers listed in the check results.",
+			},
+			Links: []checks.Link{{Title: "MESOS-9868", URL: "https://issues.apache.org/jira/browse/MESOS-9868"}},
+		},
+		Run: builder.MustBuild(),
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 func collectMasters(host bundle.Host) checks.Result {
@@ -112,7 +128,8 @@ func collectMasters(host bundle.Host) checks.Result {
 		}
 	}
 	return checks.Result{
-		Value: summary,
+		Status: checks.SOK,
+		Value:  summary,
 	}
 }
 
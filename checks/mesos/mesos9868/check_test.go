@@ -0,0 +1,33 @@
+package mesos9868
+
+import (
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle/bundletest"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+const state = `{"frameworks": [{"name": "marathon", "tasks": [{"name": "app", "statuses": [{"timestamp": 1,
+	"container_status": {"container_id": {"value": "c1"},
+	"network_infos": [{"ip_addresses": [{"ip_address": "9.0.0.1"}]}]}}]}]}]}`
+
+func containers(ip string) string {
+	return `[{"container_id": "c1", "status": {"network_infos": [{"ip_addresses": [{"ip_address": "` + ip + `"}]}]}}]`
+}
+
+func TestIPsMatch(t *testing.T) {
+	b := bundletest.New(t)
+	b.Master("10.0.0.1").File("mesos-master-state", state)
+	b.Agent("10.0.0.2").File("mesos-agent-containers", containers("9.0.0.1"))
+	results := bundletest.RunCheck(t, "mesos-9868", b.Build())
+	bundletest.AssertCounts(t, results, 1, 0, 0)
+}
+
+func TestIPsDiffer(t *testing.T) {
+	b := bundletest.New(t)
+	b.Master("10.0.0.1").File("mesos-master-state", state)
+	b.Agent("10.0.0.2").File("mesos-agent-containers", containers("9.0.0.2"))
+	results := bundletest.RunCheck(t, "mesos-9868", b.Build())
+	bundletest.AssertStatus(t, results, checks.SProblem)
+	bundletest.AssertHostStatus(t, results, "10.0.0.2", checks.SProblem)
+}
@@ -9,15 +9,25 @@ import (
 
 func init() {
 	check := checks.Check{
-		Name: "offered-resources",
-		Description: "Checks if any framework is hoarding resources by considering them for a long time. " +
-			"Example: https://jira.d2iq.com/browse/COPS-211",
+		Name:           "offered-resources",
+		Description:    "Checks if any framework is hoarding resources by considering them for a long time",
 		Cure:           "Update the framework to a new version or restart it to reset",
 		OKSummary:      "No frameworks hoarding resources",
 		ProblemSummary: "Some of the frameworks is hoarding resources",
-		Run:            checkFunc,
+		Requires:       []bundle.FileTypeName{"mesos-master-frameworks"},
+		Doc: checks.Doc{
+			Background: "Mesos offers the resources of an agent to one framework at a time. Until the framework " +
+				"accepts or declines the offer, the resources are not available to other frameworks.",
+			Symptoms: []string{"Tasks of other frameworks are not launched although the cluster has free resources."},
+			Remediation: []string{
+				"Find the framework which holds the offers in the check results.",
+				"Update the framework to a new version or restart it to release the offers.",
+			},
+			Links: []checks.Link{{Title: "COPS-211, an example of the problem", URL: "https://jira.d2iq.com/browse/COPS-211"}},
+		},
+		Run: checkFunc,
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 func checkFunc(b bundle.Bundle) checks.Results {
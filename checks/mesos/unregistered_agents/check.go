@@ -17,9 +17,10 @@ func init() {
 			"To register an agent needs to finish its recovery, and detect the master",
 		OKSummary:      "All Mesos agents appear to be registered.",
 		ProblemSummary: "Some Mesos agents appear to be unregistered.",
+		Requires:       []bundle.FileTypeName{"mesos-master-state"},
 		Run:            check,
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 type slave struct {
@@ -49,7 +50,7 @@ func check(b bundle.Bundle) checks.Results {
 	for _, slave := range s.Slaves {
 		if !slave.Active && !slave.Deactivated {
 			var agent bundle.Host
-			agent.IP = bundle.IP(slave.HostName)
+			agent.IP = bundle.ParseIP(slave.HostName)
 			agent.Type = bundle.DTAgent
 			if v := slave.Attributes["public_ip"]; v == "true" {
 				agent.Type = bundle.DTPublicAgent
@@ -64,7 +65,7 @@ func check(b bundle.Bundle) checks.Results {
 	}
 	for _, slave := range s.RecoveredSlaves {
 		var agent bundle.Host
-		agent.IP = bundle.IP(slave.HostName)
+		agent.IP = bundle.ParseIP(slave.HostName)
 		agent.Type = bundle.DTAgent
 		if v := slave.Attributes["public_ip"]; v == "true" {
 			agent.Type = bundle.DTPublicAgent
@@ -9,7 +9,10 @@ import (
 )
 
 func TestCheckPositive(t *testing.T) {
-	check := checks.GetCheck("mesos-unregistered-agents")
+	check, err := checks.GetCheck("mesos-unregistered-agents")
+	if err != nil {
+		t.Fatal(err)
+	}
 	b, err := bundle.New("test_bundles/non_active_agents")
 	if err != nil {
 		t.Fatal(err)
@@ -18,9 +21,9 @@ func TestCheckPositive(t *testing.T) {
 	if len(problems) != 2 {
 		t.Fatalf("Expected 2 problems, observe %v", len(problems))
 	}
-	problemsMap := make(map[bundle.IP]checks.Result)
+	problemsMap := make(map[string]checks.Result)
 	for _, p := range problems {
-		problemsMap[p.Host.IP] = p
+		problemsMap[p.Host.IP.String()] = p
 	}
 	if _, ok := problemsMap["10.0.3.6"]; !ok {
 		t.Fatal("Problems should contain agent 10.0.3.6")
@@ -44,7 +47,10 @@ func TestCheckPositive(t *testing.T) {
 }
 
 func TestCheckNegative(t *testing.T) {
-	check := checks.GetCheck("mesos-unregistered-agents")
+	check, err := checks.GetCheck("mesos-unregistered-agents")
+	if err != nil {
+		t.Fatal(err)
+	}
 	b, err := bundle.New("test_bundles/ok")
 	if err != nil {
 		t.Fatal(err)
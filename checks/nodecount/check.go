@@ -16,7 +16,7 @@ func init() {
 		ProblemSummary: "Cluster doesn't have correct amount of masters or agents.",
 		Run:            checkFunc,
 	}
-	checks.RegisterCheck(check)
+	checks.MustRegisterCheck(check)
 }
 
 func checkFunc(b bundle.Bundle) checks.Results {
@@ -12,7 +12,10 @@ func TestOK(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	c := checks.GetCheck("node-count")
+	c, err := checks.GetCheck("node-count")
+	if err != nil {
+		t.Fatal(err)
+	}
 	results := c.Run(b)
 	if len(results.Undefined()) > 0 {
 		t.Errorf("Expected zero undefined results, observed %v.", results.Undefined())
@@ -30,7 +33,10 @@ func TestMastersCount(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	c := checks.GetCheck("node-count")
+	c, err := checks.GetCheck("node-count")
+	if err != nil {
+		t.Fatal(err)
+	}
 	results := c.Run(b)
 	if len(results.Undefined()) > 0 {
 		t.Errorf("Expected zero undefined results, observed %v.", results.Undefined())
@@ -0,0 +1,136 @@
+package checks
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ParamType defines type of the check parameter value.
+type ParamType string
+
+const (
+	// PTInt is an integer parameter.
+	PTInt ParamType = "int"
+	// PTFloat is a floating point parameter.
+	PTFloat = "float"
+	// PTString is a string parameter.
+	PTString = "string"
+	// PTBool is a boolean parameter.
+	PTBool = "bool"
+)
+
+// Param is a tunable check parameter, e.g. a threshold. Checks declare their
+// parameters with default values; users can override them with a config file
+// or with the command line flags.
+type Param struct {
+	Name        string
+	Description string
+	Type        ParamType
+	Default     interface{}
+	mu          sync.RWMutex
+	value       interface{}
+}
+
+// IntParam creates a new integer parameter.
+func IntParam(name string, def int, description string) *Param {
+	return &Param{Name: name, Description: description, Type: PTInt, Default: def}
+}
+
+// FloatParam creates a new floating point parameter.
+func FloatParam(name string, def float64, description string) *Param {
+	return &Param{Name: name, Description: description, Type: PTFloat, Default: def}
+}
+
+// StringParam creates a new string parameter.
+func StringParam(name string, def string, description string) *Param {
+	return &Param{Name: name, Description: description, Type: PTString, Default: def}
+}
+
+// BoolParam creates a new boolean parameter.
+func BoolParam(name string, def bool, description string) *Param {
+	return &Param{Name: name, Description: description, Type: PTBool, Default: def}
+}
+
+// Value returns the effective value of the parameter.
+func (p *Param) Value() interface{} {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.value != nil {
+		return p.value
+	}
+	return p.Default
+}
+
+// IsSet returns true if the default value was overridden.
+func (p *Param) IsSet() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.value != nil
+}
+
+// Int returns the effective value of the integer parameter.
+func (p *Param) Int() int {
+	return p.Value().(int)
+}
+
+// Float returns the effective value of the floating point parameter.
+func (p *Param) Float() float64 {
+	return p.Value().(float64)
+}
+
+// String returns the effective value of the string parameter.
+func (p *Param) String() string {
+	return p.Value().(string)
+}
+
+// Bool returns the effective value of the boolean parameter.
+func (p *Param) Bool() bool {
+	return p.Value().(bool)
+}
+
+// Set parses the string according to the parameter type and overrides
+// the default value.
+func (p *Param) Set(s string) error {
+	var v interface{}
+	var err error
+	switch p.Type {
+	case PTInt:
+		v, err = strconv.Atoi(s)
+	case PTFloat:
+		v, err = strconv.ParseFloat(s, 64)
+	case PTString:
+		v = s
+	case PTBool:
+		v, err = strconv.ParseBool(s)
+	default:
+		err = fmt.Errorf("unknown parameter type %v", p.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q of the %v parameter %v: %v", s, p.Type, p.Name, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.value = v
+	return nil
+}
+
+// Reset restores the default value.
+func (p *Param) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.value = nil
+}
+
+// Params is a list of check parameters.
+type Params []*Param
+
+// Get returns the parameter by its name or nil if there is no such parameter.
+func (ps Params) Get(name string) *Param {
+	for _, p := range ps {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+// Plugins are executables which implement the following protocol:
+//
+// `<plugin> manifest` prints the Manifest JSON object to the stdout.
+//
+// `<plugin> run` reads the Request JSON object from the stdin and prints
+// a JSON array of Result objects to the stdout.
+const (
+	cmdManifest = "manifest"
+	cmdRun      = "run"
+)
+
+// DefaultTimeout limits the execution time of a plugin command.
+const DefaultTimeout = time.Minute
+
+// Manifest describes a check implemented by a plugin.
+type Manifest struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	Cure           string   `json:"cure"`
+	OKSummary      string   `json:"okSummary"`
+	ProblemSummary string   `json:"problemSummary"`
+	Tags           []string `json:"tags"`
+	// Requires are the file types the check needs; see checks.Check.Requires.
+	Requires []bundle.FileTypeName `json:"requires"`
+}
+
+// Host describes a bundle host in the Request. Paths lists all the host
+// directories if the bundle is merged from several bundles; Path is the first one.
+type Host struct {
+	IP    bundle.IP      `json:"ip"`
+	Type  bundle.DirType `json:"type"`
+	Path  string         `json:"path"`
+	Paths []string       `json:"paths"`
+}
+
+// Request is passed to the plugin on the stdin when it runs the check.
+// Roots lists all the root directories of a merged bundle; Root is the first one.
+type Request struct {
+	Root  string   `json:"root"`
+	Roots []string `json:"roots"`
+	Hosts []Host   `json:"hosts"`
+}
+
+// Result is a check result returned by the plugin. Host is an IP address of
+// one of the bundle hosts; it is optional.
+type Result struct {
+	Status checks.Status `json:"status"`
+	Host   bundle.IP     `json:"host"`
+	Value  string        `json:"value"`
+}
+
+// RegisterPlugins discovers the executables in the dir directory and registers
+// the checks they implement. Each plugin command is killed after the timeout.
+// On Windows, the executables are the files with the extensions from PATHEXT.
+func RegisterPlugins(dir string, timeout time.Duration) error {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if !info.Mode().IsRegular() || !isExecutable(info) {
+			continue
+		}
+		p := plugin{path: filepath.Join(dir, info.Name()), timeout: timeout}
+		m, err := p.manifest()
+		if err != nil {
+			return fmt.Errorf("cannot read manifest of plugin %v: %v", p.path, err)
+		}
+		if err := checks.RegisterCheck(checks.Check{
+			Name:           m.Name,
+			Description:    m.Description,
+			Cure:           m.Cure,
+			OKSummary:      m.OKSummary,
+			ProblemSummary: m.ProblemSummary,
+			Tags:           m.Tags,
+			Requires:       m.Requires,
+			Run:            p.run,
+		}); err != nil {
+			return fmt.Errorf("cannot register plugin %v: %v", p.path, err)
+		}
+	}
+	return nil
+}
+
+type plugin struct {
+	path    string
+	timeout time.Duration
+}
+
+// waitDelay limits the time to wait for the plugin output after the plugin
+// is killed on timeout.
+const waitDelay = time.Second
+
+// exec launches the plugin with the given command, passes the stdin to it,
+// and returns its stdout. On timeout, the plugin is killed along with the
+// processes it started, which would otherwise hold its output open.
+func (p plugin) exec(command string, stdin []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	cmd := exec.Command(p.path, command)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("plugin %v failed: %v", filepath.Base(p.path), err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		killProcessGroup(cmd)
+		select {
+		case <-done:
+		case <-time.After(waitDelay):
+		}
+		return nil, fmt.Errorf("plugin %v timed out after %v", filepath.Base(p.path), p.timeout)
+	}
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return nil, fmt.Errorf("plugin %v failed: %v", filepath.Base(p.path), err)
+		}
+		return nil, fmt.Errorf("plugin %v failed: %v: %v", filepath.Base(p.path), err, msg)
+	}
+	return stdout.Bytes(), nil
+}
+
+func (p plugin) manifest() (Manifest, error) {
+	var m Manifest
+	out, err := p.exec(cmdManifest, nil)
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(out, &m)
+	return m, err
+}
+
+// run implements the checks.CheckBundleFunc.
+func (p plugin) run(b bundle.Bundle) checks.Results {
+	req := Request{Root: b.Path, Roots: b.Paths(), Hosts: make([]Host, 0, len(b.Hosts))}
+	hosts := make(map[bundle.IP]bundle.Host, len(b.Hosts))
+	for _, h := range b.Hosts {
+		req.Hosts = append(req.Hosts, Host{IP: h.IP, Type: h.Type, Path: h.Path, Paths: h.Paths()})
+		hosts[h.IP] = h
+	}
+	stdin, err := json.Marshal(req)
+	if err != nil {
+		return undefined(err)
+	}
+	out, err := p.exec(cmdRun, stdin)
+	if err != nil {
+		return undefined(err)
+	}
+	var pluginResults []Result
+	if err := json.Unmarshal(out, &pluginResults); err != nil {
+		return undefined(fmt.Errorf("cannot parse plugin %v output: %v", filepath.Base(p.path), err))
+	}
+	if len(pluginResults) == 0 {
+		return undefined(fmt.Errorf("plugin %v returned no results", filepath.Base(p.path)))
+	}
+	results := make(checks.Results, 0, len(pluginResults))
+	for _, r := range pluginResults {
+		result := checks.Result{Status: r.Status, Value: r.Value}
+		switch r.Status {
+		case checks.SOK, checks.SProblem, checks.SUndefined, checks.SSkipped:
+		default:
+			result.Status = checks.SUndefined
+			result.Value = fmt.Sprintf("Unknown status %q: %v", r.Status, r.Value)
+		}
+		if !r.Host.IsZero() {
+			h, ok := hosts[r.Host]
+			if !ok {
+				h.IP = r.Host
+			}
+			result.Host = h
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func undefined(err error) checks.Results {
+	return checks.Results{{
+		Status: checks.SUndefined,
+		Value:  err.Error(),
+	}}
+}
+
+// hasExecutableExt returns true if the file name has one of the extensions
+// from the PATHEXT list, e.g. ".COM;.EXE;.BAT;.CMD". Extensions are
+// case-insensitive.
+func hasExecutableExt(name string, pathext string) bool {
+	ext := filepath.Ext(name)
+	if ext == "" {
+		return false
+	}
+	for _, e := range strings.Split(pathext, ";") {
+		if strings.EqualFold(ext, strings.TrimSpace(e)) {
+			return true
+		}
+	}
+	return false
+}
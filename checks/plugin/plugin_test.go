@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func TestRegisterPlugins(t *testing.T) {
+	if err := RegisterPlugins("test_plugins/ok", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := getCheck(t, "plugin-agents")
+	if len(c.Tags) != 1 || c.Tags[0] != "test" {
+		t.Errorf("Expected tags [test], observed %v", c.Tags)
+	}
+	results := c.Run(b)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, observed %v", len(results))
+	}
+	problems := results.Problems()
+	if len(problems) != 1 {
+		t.Fatalf("Expected 1 problem, observed %v", len(problems))
+	}
+	if problems[0].Host.IP.String() != "10.0.0.2" || problems[0].Host.Type != bundle.DTAgent {
+		t.Errorf("Expected problem on agent 10.0.0.2, observed %v %v", problems[0].Host.Type, problems[0].Host.IP)
+	}
+}
+
+func TestCrashIsUndefined(t *testing.T) {
+	if err := RegisterPlugins("test_plugins/crash", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := getCheck(t, "plugin-crash").Run(b)
+	if results.Status() != checks.SUndefined {
+		t.Fatalf("Expected Status = UNDEFINED, observed %v", results.Status())
+	}
+	if !strings.Contains(results[0].Value.(string), "something went wrong") {
+		t.Errorf("Expected stderr in the result, observed %v", results[0].Value)
+	}
+}
+
+func TestTimeoutIsUndefined(t *testing.T) {
+	if err := RegisterPlugins("test_plugins/timeout", 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	results := getCheck(t, "plugin-timeout").Run(b)
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Expected the plugin to be killed on timeout, it ran for %v", d)
+	}
+	if results.Status() != checks.SUndefined {
+		t.Fatalf("Expected Status = UNDEFINED, observed %v", results.Status())
+	}
+	if !strings.Contains(results[0].Value.(string), "timed out") {
+		t.Errorf("Expected timeout in the result, observed %v", results[0].Value)
+	}
+}
+
+func getCheck(t *testing.T, name string) checks.Check {
+	c, err := checks.GetCheck(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestHasExecutableExt(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"check.exe":  true,
+		"check.CMD":  true,
+		"check.bat":  true,
+		"check.sh":   false,
+		"README.md":  false,
+		"check":      false,
+		"check.exe.": false,
+	} {
+		if observed := hasExecutableExt(name, ".COM;.EXE;.BAT;.CMD"); observed != expected {
+			t.Errorf("hasExecutableExt(%q): expected %v, observed %v", name, expected, observed)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func isExecutable(info os.FileInfo) bool {
+	return info.Mode().Perm()&0111 != 0
+}
+
+// setProcessGroup makes the plugin the leader of a new process group, so
+// killProcessGroup reaches the processes the plugin starts.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
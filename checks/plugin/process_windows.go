@@ -0,0 +1,26 @@
+package plugin
+
+import (
+	"os"
+	"os/exec"
+)
+
+// defaultPathExt is used if the PATHEXT environment variable is not set.
+const defaultPathExt = ".COM;.EXE;.BAT;.CMD"
+
+// isExecutable checks the file extension because Windows has no exec bits.
+func isExecutable(info os.FileInfo) bool {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = defaultPathExt
+	}
+	return hasExecutableExt(info.Name(), pathext)
+}
+
+// Windows has no process groups which could be killed at once, so only the
+// plugin itself is killed; exec stops waiting for its output after waitDelay.
+func setProcessGroup(*exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
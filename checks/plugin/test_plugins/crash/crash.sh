@@ -0,0 +1,10 @@
+#!/bin/sh
+case "$1" in
+manifest)
+	echo '{"name": "plugin-crash", "description": "Crashes", "cure": "None"}'
+	;;
+run)
+	echo "something went wrong" >&2
+	exit 1
+	;;
+esac
@@ -0,0 +1,11 @@
+#!/bin/sh
+# Reports a problem on every agent.
+case "$1" in
+manifest)
+	echo '{"name": "plugin-agents", "description": "Reports every agent", "cure": "None", "tags": ["test"]}'
+	;;
+run)
+	cat > /dev/null
+	echo '[{"status": "OK", "host": "10.0.0.1"}, {"status": "PROBLEM", "host": "10.0.0.2", "value": "agent"}]'
+	;;
+esac
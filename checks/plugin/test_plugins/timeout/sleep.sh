@@ -0,0 +1,10 @@
+#!/bin/sh
+case "$1" in
+manifest)
+	echo '{"name": "plugin-timeout", "description": "Sleeps forever", "cure": "None"}'
+	;;
+run)
+	# The child process keeps the stdout open after the plugin is killed.
+	sleep 10
+	;;
+esac
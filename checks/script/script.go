@@ -0,0 +1,167 @@
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+// Ext is the extension of the Starlark check scripts.
+const Ext = ".star"
+
+// RegisterChecks executes all the Starlark scripts found in the dir directory
+// and registers the checks they declare with the register_check function.
+// Scripts cannot access the file system directly, the only way to read
+// the bundle files is through the bundle and host objects passed to the
+// check functions.
+func RegisterChecks(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if err := RegisterFile(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterFile executes the Starlark script and registers the checks it declares.
+func RegisterFile(path string) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var registered []checks.Check
+	predeclared := starlark.StringDict{
+		"register_check": starlark.NewBuiltin("register_check", registerCheck(&registered)),
+		"result":         starlark.NewBuiltin("result", newResult),
+		"json":           json.Module,
+		"OK":             starlark.String(checks.SOK),
+		"PROBLEM":        starlark.String(checks.SProblem),
+		"UNDEFINED":      starlark.String(checks.SUndefined),
+		"SKIPPED":        starlark.String(checks.SSkipped),
+	}
+	thread := newThread(path)
+	if _, err = starlark.ExecFile(thread, path, src, predeclared); err != nil {
+		return fmt.Errorf("cannot execute script %v: %v", path, err)
+	}
+	for _, c := range registered {
+		if err := checks.RegisterCheck(c); err != nil {
+			return fmt.Errorf("cannot register check from script %v: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// newThread creates a Starlark thread. The thread does not support the load
+// statement, so the scripts cannot read anything but the bundle files.
+func newThread(name string) *starlark.Thread {
+	return &starlark.Thread{Name: name}
+}
+
+func registerCheck(registered *[]checks.Check) func(*starlark.Thread, *starlark.Builtin,
+	starlark.Tuple, []starlark.Tuple) (starlark.Value, error) {
+	return func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple,
+		kwargs []starlark.Tuple) (starlark.Value, error) {
+		var c checks.Check
+		var run starlark.Callable
+		var requires *starlark.List
+		if err := starlark.UnpackArgs(b.Name(), args, kwargs,
+			"name", &c.Name,
+			"description", &c.Description,
+			"cure", &c.Cure,
+			"run", &run,
+			"ok_summary?", &c.OKSummary,
+			"problem_summary?", &c.ProblemSummary,
+			"requires?", &requires,
+		); err != nil {
+			return nil, err
+		}
+		if requires != nil {
+			for i := 0; i < requires.Len(); i++ {
+				t, ok := starlark.AsString(requires.Index(i))
+				if !ok {
+					return nil, fmt.Errorf("%v: requires must be a list of file type names", b.Name())
+				}
+				c.Requires = append(c.Requires, bundle.FileTypeName(t))
+			}
+		}
+		c.Run = checkFunc(thread.Name, run)
+		*registered = append(*registered, c)
+		return starlark.None, nil
+	}
+}
+
+// checkFunc wraps the Starlark run function into the checks.CheckBundleFunc.
+// If the script reads a partially read file, all its results are partial.
+func checkFunc(script string, run starlark.Callable) checks.CheckBundleFunc {
+	return func(b bundle.Bundle) checks.Results {
+		thread := newThread(script)
+		partial := false
+		thread.SetLocal(partialKey, &partial)
+		results := runScript(thread, run, b)
+		for i := range results {
+			results[i].Partial = results[i].Partial || partial
+		}
+		return results
+	}
+}
+
+func runScript(thread *starlark.Thread, run starlark.Callable, b bundle.Bundle) checks.Results {
+	v, err := starlark.Call(thread, run, starlark.Tuple{&bundleValue{b}}, nil)
+	if err != nil {
+		return undefined(err)
+	}
+	results, err := toResults(v)
+	if err != nil {
+		return undefined(err)
+	}
+	return results
+}
+
+func undefined(err error) checks.Results {
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		err = fmt.Errorf("%v", evalErr.Backtrace())
+	}
+	return checks.Results{{
+		Status: checks.SUndefined,
+		Value:  "Script error: " + err.Error(),
+	}}
+}
+
+// toResults converts a value returned by the Starlark run function to the
+// check results. The function may return a single result or an iterable of results.
+func toResults(v starlark.Value) (checks.Results, error) {
+	if r, ok := v.(*result); ok {
+		return checks.Results{r.Result}, nil
+	}
+	iterable, ok := v.(starlark.Iterable)
+	if !ok {
+		return nil, fmt.Errorf("run function should return a result or a list of results, got %v", v.Type())
+	}
+	var results checks.Results
+	iter := iterable.Iterate()
+	defer iter.Done()
+	var x starlark.Value
+	for iter.Next(&x) {
+		r, ok := x.(*result)
+		if !ok {
+			return nil, fmt.Errorf("run function should return results only, got %v", x.Type())
+		}
+		results = append(results, r.Result)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("run function returned no results")
+	}
+	return results, nil
+}
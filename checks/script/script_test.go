@@ -0,0 +1,76 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/bundle/bundletest"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func TestRegisterChecks(t *testing.T) {
+	if err := RegisterChecks("test_scripts/ok"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := getCheck(t, "script-dcos-version").Run(b)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, observed %v", len(results))
+	}
+	problems := results.Problems()
+	if len(problems) != 1 {
+		t.Fatalf("Expected 1 problem, observed %v", len(problems))
+	}
+	if problems[0].Host.IP.String() != "10.0.0.2" {
+		t.Errorf("Expected problem on 10.0.0.2, observed %v", problems[0].Host.IP)
+	}
+	if problems[0].Value != "DC/OS version is 2.0.3" {
+		t.Errorf("Unexpected problem value: %v", problems[0].Value)
+	}
+
+	results = getCheck(t, "script-scan").Run(b)
+	if results.Status() != checks.SOK || results[0].Value != "1" {
+		t.Errorf("Expected OK with value 1, observed %v", results)
+	}
+
+	results = getCheck(t, "script-broken").Run(b)
+	if results.Status() != checks.SUndefined {
+		t.Fatalf("Expected Status = UNDEFINED, observed %v", results.Status())
+	}
+	if !strings.Contains(results[0].Value.(string), "no-such-file-type") {
+		t.Errorf("Expected error about unknown file type, observed %v", results[0].Value)
+	}
+}
+
+func TestLoadIsForbidden(t *testing.T) {
+	if err := RegisterChecks("test_scripts/load"); err == nil {
+		t.Fatal("Expected error when a script uses the load statement")
+	}
+}
+
+func TestPartialFilesMakePartialResults(t *testing.T) {
+	if err := RegisterChecks("test_scripts/partial"); err != nil {
+		t.Fatal(err)
+	}
+	b := bundletest.New(t)
+	b.Master("10.0.0.1").TruncatedGzipFile("dcos-version", `{"version": "2.1.0"}`)
+	built := b.Build()
+	for _, name := range []string{"script-partial-read", "script-partial-scan"} {
+		results := getCheck(t, name).Run(built)
+		if results.Status() != checks.SOK || !results.Partial() {
+			t.Errorf("%v: expected partial OK results, observed %v", name, results)
+		}
+	}
+}
+
+func getCheck(t *testing.T, name string) checks.Check {
+	c, err := checks.GetCheck(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
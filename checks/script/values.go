@@ -0,0 +1,287 @@
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+// directory exposes read-only access to the files of a bundle directory.
+// Files can be accessed only by their file type names.
+type directory struct {
+	bundle.Directory
+}
+
+func (d directory) attr(name string) (starlark.Value, bool) {
+	switch name {
+	case "type":
+		return starlark.String(d.Type), true
+	case "has":
+		return starlark.NewBuiltin(name, d.has), true
+	case "read":
+		return starlark.NewBuiltin(name, d.read), true
+	case "read_json":
+		return starlark.NewBuiltin(name, d.readJSON), true
+	case "scan":
+		return starlark.NewBuiltin(name, d.scan), true
+	}
+	return nil, false
+}
+
+var directoryAttrs = []string{"has", "read", "read_json", "scan", "type"}
+
+// fileType unpacks the file type name argument and verifies that the file type
+// can be found in the directory.
+func (d directory) fileType(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+	pairs ...interface{}) (bundle.FileTypeName, error) {
+	var name string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, append([]interface{}{"file_type", &name}, pairs...)...); err != nil {
+		return "", err
+	}
+	t, err := bundle.GetFileType(bundle.FileTypeName(name))
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", b.Name(), err)
+	}
+	if !t.ExistsOn(d.Type) {
+		return "", fmt.Errorf("%v: file type %v cannot be found on %v hosts", b.Name(), name, d.Type)
+	}
+	return t.Name, nil
+}
+
+func (d directory) has(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+	t, err := d.fileType(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	f, err := d.OpenFile(t)
+	if err != nil {
+		return starlark.False, nil
+	}
+	_ = f.Close()
+	return starlark.True, nil
+}
+
+func (d directory) read(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+	t, err := d.fileType(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	f, err := d.OpenFile(t)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	notePartial(thread, f)
+	return starlark.String(data), nil
+}
+
+func (d directory) readJSON(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+	data, err := d.read(thread, b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.Call(thread, json.Module.Members["decode"], starlark.Tuple{data}, nil)
+}
+
+// scan calls the callback for each line of the file with the line number and
+// the line without the trailing newline. It stops if the callback returns True.
+func (d directory) scan(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+	var callback starlark.Callable
+	t, err := d.fileType(b, args, kwargs, "callback", &callback)
+	if err != nil {
+		return nil, err
+	}
+	var callbackErr error
+	f, err := d.ScanLines(t, func(n int, line string) bool {
+		v, err := starlark.Call(thread, callback,
+			starlark.Tuple{starlark.MakeInt(n), starlark.String(strings.TrimRight(line, "\r\n"))}, nil)
+		if err != nil {
+			callbackErr = err
+			return true
+		}
+		return bool(v.Truth())
+	})
+	if callbackErr != nil {
+		return nil, callbackErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	notePartial(thread, f)
+	return starlark.None, nil
+}
+
+// partialKey is the thread-local key of the flag which is set when the script
+// reads a partially read file, so its results are flagged as partial.
+const partialKey = "partial"
+
+func notePartial(thread *starlark.Thread, f bundle.File) {
+	if bundle.Partial(f) == nil {
+		return
+	}
+	if partial, ok := thread.Local(partialKey).(*bool); ok {
+		*partial = true
+	}
+}
+
+// host is a Starlark representation of the bundle.Host.
+type host struct {
+	host bundle.Host
+}
+
+var _ starlark.HasAttrs = (*host)(nil)
+
+func (h *host) String() string        { return fmt.Sprintf("%v %v", h.host.Type, h.host.IP) }
+func (h *host) Type() string          { return "host" }
+func (h *host) Freeze()               {}
+func (h *host) Truth() starlark.Bool  { return starlark.True }
+func (h *host) Hash() (uint32, error) { return starlark.String(h.host.IP.String()).Hash() }
+
+func (h *host) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "ip":
+		return starlark.String(h.host.IP.String()), nil
+	case "address_family":
+		return starlark.String(h.host.IP.Family), nil
+	case "hostname":
+		return starlark.String(h.host.IP.Hostname), nil
+	}
+	if v, ok := (directory{h.host.Directory}).attr(name); ok {
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (h *host) AttrNames() []string {
+	return append([]string{"ip", "address_family", "hostname"}, directoryAttrs...)
+}
+
+// bundleValue is a Starlark representation of the bundle.Bundle.
+type bundleValue struct {
+	bundle bundle.Bundle
+}
+
+var _ starlark.HasAttrs = (*bundleValue)(nil)
+
+func (b *bundleValue) String() string        { return "bundle" }
+func (b *bundleValue) Type() string          { return "bundle" }
+func (b *bundleValue) Freeze()               {}
+func (b *bundleValue) Truth() starlark.Bool  { return starlark.True }
+func (b *bundleValue) Hash() (uint32, error) { return 0, fmt.Errorf("unhashable type: bundle") }
+
+func (b *bundleValue) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "hosts":
+		return hostList(b.bundle.Hosts), nil
+	case "masters":
+		return hostList(b.bundle.Masters()), nil
+	case "agents":
+		return hostList(b.bundle.Agents()), nil
+	case "public_agents":
+		return hostList(b.bundle.PublicAgents()), nil
+	}
+	if v, ok := (directory{b.bundle.Directory}).attr(name); ok {
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (b *bundleValue) AttrNames() []string {
+	names := append([]string{"agents", "hosts", "masters", "public_agents"}, directoryAttrs...)
+	sort.Strings(names)
+	return names
+}
+
+func hostList(hosts []bundle.Host) *starlark.List {
+	values := make([]starlark.Value, 0, len(hosts))
+	for _, h := range hosts {
+		values = append(values, &host{h})
+	}
+	list := starlark.NewList(values)
+	list.Freeze()
+	return list
+}
+
+// result is a Starlark representation of the checks.Result.
+type result struct {
+	checks.Result
+}
+
+var _ starlark.HasAttrs = (*result)(nil)
+
+func (r *result) String() string        { return fmt.Sprintf("result(%v, %v)", r.Status, r.Value) }
+func (r *result) Type() string          { return "result" }
+func (r *result) Freeze()               {}
+func (r *result) Truth() starlark.Bool  { return starlark.True }
+func (r *result) Hash() (uint32, error) { return 0, fmt.Errorf("unhashable type: result") }
+
+func (r *result) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "status":
+		return starlark.String(r.Status), nil
+	case "value":
+		if r.Value == nil {
+			return starlark.None, nil
+		}
+		return starlark.String(fmt.Sprint(r.Value)), nil
+	case "host":
+		if !r.IsHostSet() {
+			return starlark.None, nil
+		}
+		return &host{r.Host}, nil
+	}
+	return nil, nil
+}
+
+func (r *result) AttrNames() []string {
+	return []string{"host", "status", "value"}
+}
+
+// newResult implements the result(status, value=None, host=None) builtin.
+func newResult(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+	var status string
+	var value starlark.Value = starlark.None
+	var h starlark.Value = starlark.None
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs,
+		"status", &status, "value?", &value, "host?", &h); err != nil {
+		return nil, err
+	}
+	r := &result{}
+	switch checks.Status(status) {
+	case checks.SOK, checks.SProblem, checks.SUndefined, checks.SSkipped:
+		r.Status = checks.Status(status)
+	default:
+		return nil, fmt.Errorf("%v: unknown status %q", b.Name(), status)
+	}
+	switch v := value.(type) {
+	case starlark.NoneType:
+	case starlark.String:
+		r.Value = v.GoString()
+	default:
+		r.Value = v.String()
+	}
+	switch v := h.(type) {
+	case starlark.NoneType:
+	case *host:
+		r.Host = v.host
+	default:
+		return nil, fmt.Errorf("%v: host should be a host, got %v", b.Name(), h.Type())
+	}
+	return r, nil
+}
@@ -2,6 +2,7 @@ package checks
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"path"
 	"regexp"
@@ -27,11 +28,15 @@ type SearchCheck struct {
 	FailIfNotFound       bool                `yaml:"failIfNotFound"`       // Optional, default false
 	errorRegexp          *regexp.Regexp
 	cureRegexp           *regexp.Regexp
+	max                  *Param
 }
 
-func (c SearchCheck) checkFunc() CheckBundleFunc {
+func (c SearchCheck) checkFunc() (CheckBundleFunc, error) {
 	builder := CheckFuncBuilder{}
-	t := bundle.GetFileType(c.FileTypeName)
+	t, err := bundle.GetFileType(c.FileTypeName)
+	if err != nil {
+		return nil, err
+	}
 	for _, dirType := range t.DirTypes {
 		switch dirType {
 		case bundle.DTMaster:
@@ -40,6 +45,10 @@ func (c SearchCheck) checkFunc() CheckBundleFunc {
 			builder.CheckAgents = c.search
 		case bundle.DTPublicAgent:
 			builder.CheckPublicAgents = c.search
+		case bundle.DTControlPlane:
+			builder.CheckControlPlanes = c.search
+		case bundle.DTWorker:
+			builder.CheckWorkers = c.search
 		}
 	}
 	builder.Aggregate = aggregate
@@ -71,6 +80,7 @@ func aggregate(r Results) Results {
 	results = append(results, problems...)
 	results = append(results, r.Undefined()...)
 	results = append(results, r.OKs()...)
+	results = append(results, r.Skipped()...)
 	return results
 }
 
@@ -102,10 +112,16 @@ func (c SearchCheck) search(host bundle.Host) Result {
 			return c.cureRegexp.MatchString(line)
 		}
 	}
-	f := func(n int, line string) bool {
+	// Rotated logs are scanned as one stream; n is the line number in
+	// the stream, and lastFile is the physical file of the last error.
+	var n int
+	var lastFile bundle.File
+	f := func(physical bundle.File, _ int, line string) bool {
+		n++
 		if matchError(line) {
 			count++
 			lastN = n
+			lastFile = physical
 			if c.FailIfNotFound {
 				return true
 			}
@@ -116,67 +132,121 @@ func (c SearchCheck) search(host bundle.Host) Result {
 		return false
 	}
 
-	file, err := host.ScanLines(c.FileTypeName, f)
+	evidence := []bundle.FileTypeName{c.FileTypeName}
+	file, err := host.ScanFiles(c.FileTypeName, f)
+	if errors.Is(err, bundle.ErrFileNotFound) {
+		return Result{
+			Status:   SSkipped,
+			Host:     host,
+			Value:    "Skipped: " + err.Error(),
+			Evidence: evidence,
+		}
+	}
 	if err != nil {
 		return Result{
-			Status: SUndefined,
-			Host:   host,
-			Value:  "Couldn't check. Error: " + err.Error(),
+			Status:   SUndefined,
+			Host:     host,
+			Value:    "Couldn't check. Error: " + err.Error(),
+			Evidence: evidence,
 		}
 	}
+	partial := bundle.Partial(file) != nil
 	if c.FailIfNotFound {
 		if count == 0 {
 			return Result{
-				Status: SProblem,
-				Value:  "Expected pattern not found in " + file.Name(),
+				Status:   SProblem,
+				Value:    "Expected pattern not found in " + file.Name(),
+				Partial:  partial,
+				Evidence: evidence,
 			}
 		}
 	} else {
-		if count > c.Max && lastN > lastNCure {
+		if count > c.max.Int() && lastN > lastNCure {
 			return Result{
-				Status: SProblem,
-				Value:  problemValue{count, file},
+				Status:   SProblem,
+				Value:    problemValue{count, lastFile},
+				Partial:  partial,
+				Evidence: evidence,
 			}
 		}
 	}
 	return Result{
-		Status: SOK,
+		Status:   SOK,
+		Partial:  partial,
+		Evidence: evidence,
 	}
 }
 
 //go:embed search_checks.yaml
 var searchChecksYAML []byte
 
-func RegisterSearchChecks() {
+// RegisterSearchChecks registers the built-in search checks.
+func RegisterSearchChecks() error {
+	return registerSearchChecksYAML(searchChecksYAML)
+}
+
+// registerSearchChecksYAML registers search checks described in the YAML
+// document. Invalid checks are skipped; the function returns an error which
+// lists all of them.
+func registerSearchChecksYAML(y []byte) error {
 	var searchChecks []SearchCheck
-	err := yaml.Unmarshal(searchChecksYAML, &searchChecks)
+	err := yaml.Unmarshal(y, &searchChecks)
 	if err != nil {
-		panic("Cannot read search checks YAML: " + err.Error())
+		return fmt.Errorf("cannot read search checks YAML: %w", err)
 	}
+	var errs Errors
 	for _, c := range searchChecks {
-		if c.FileTypeName == "" {
-			panic("FileTypeName should be specified.")
+		if err := c.register(); err != nil {
+			errs = append(errs, err)
 		}
-		if c.ErrorPattern == "" {
-			panic("ErrorPattern should be set.")
-		}
-		if c.FailIfNotFound && c.CurePattern != "" {
-			panic("FailIfNotFound and CurePattern are mutually exclusive")
-		}
-		if c.IsErrorPatternRegexp {
-			c.errorRegexp = regexp.MustCompile(c.ErrorPattern)
-		}
-		if c.IsCurePatternRegexp {
-			c.cureRegexp = regexp.MustCompile(c.CurePattern)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (c SearchCheck) register() error {
+	if c.FileTypeName == "" {
+		return fmt.Errorf("%w: search check %v: FileTypeName should be specified", ErrInvalidCheck, c.Name)
+	}
+	if c.ErrorPattern == "" {
+		return fmt.Errorf("%w: search check %v: ErrorPattern should be set", ErrInvalidCheck, c.Name)
+	}
+	if c.FailIfNotFound && c.CurePattern != "" {
+		return fmt.Errorf("%w: search check %v: FailIfNotFound and CurePattern are mutually exclusive",
+			ErrInvalidCheck, c.Name)
+	}
+	var err error
+	if c.IsErrorPatternRegexp {
+		if c.errorRegexp, err = regexp.Compile(c.ErrorPattern); err != nil {
+			return fmt.Errorf("%w: search check %v: %v", ErrInvalidCheck, c.Name, err)
 		}
-		c.Run = c.checkFunc()
-		if c.FailIfNotFound {
-			c.OKSummary = fmt.Sprintf("Expected pattern \"%s\" found.", c.ErrorPattern)
-			c.ProblemSummary = fmt.Sprintf("Expected pattern \"%s\" not found.", c.ErrorPattern)
-		} else {
-			c.OKSummary = fmt.Sprintf("Error pattern \"%s\" not found.", c.ErrorPattern)
-			c.ProblemSummary = fmt.Sprintf("Error pattern \"%s\" found.", c.ErrorPattern)
+	}
+	if c.IsCurePatternRegexp {
+		if c.cureRegexp, err = regexp.Compile(c.CurePattern); err != nil {
+			return fmt.Errorf("%w: search check %v: %v", ErrInvalidCheck, c.Name, err)
 		}
-		RegisterCheck(c.Check)
 	}
+	if !c.FailIfNotFound {
+		c.max = IntParam("max", c.Max, "number of error pattern occurrences considered healthy")
+		c.Params = Params{c.max}
+	}
+	if c.Run, err = c.checkFunc(); err != nil {
+		return fmt.Errorf("%w: search check %v: %v", ErrInvalidCheck, c.Name, err)
+	}
+	if t, err := bundle.GetFileType(c.FileTypeName); err == nil && c.Flavor == "" {
+		c.Flavor = t.Flavor
+	}
+	if len(c.Requires) == 0 {
+		c.Requires = []bundle.FileTypeName{c.FileTypeName}
+	}
+	if c.FailIfNotFound {
+		c.OKSummary = fmt.Sprintf("Expected pattern \"%s\" found.", c.ErrorPattern)
+		c.ProblemSummary = fmt.Sprintf("Expected pattern \"%s\" not found.", c.ErrorPattern)
+	} else {
+		c.OKSummary = fmt.Sprintf("Error pattern \"%s\" not found.", c.ErrorPattern)
+		c.ProblemSummary = fmt.Sprintf("Error pattern \"%s\" found.", c.ErrorPattern)
+	}
+	return RegisterCheck(c.Check)
 }
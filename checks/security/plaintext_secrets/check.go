@@ -0,0 +1,120 @@
+package plaintextsecrets
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+	"github.com/mesosphere/bun/v2/secrets"
+)
+
+func init() {
+	builder := checks.CheckFuncBuilder{
+		CheckMasters:      check,
+		CheckAgents:       check,
+		CheckPublicAgents: check,
+	}
+	check := checks.Check{
+		Name: "plaintext-secrets",
+		Description: "Checks that the DC/OS configuration, Marathon app definitions, and" +
+			" the environment of the Mesos processes don't contain secrets in plain text",
+		Cure: "Rotate the exposed credentials. Store secrets in the DC/OS secret store" +
+			" and reference them from the app definitions instead of putting them" +
+			" in the environment variables or the configuration.",
+		OKSummary:      "No plain text secrets found.",
+		ProblemSummary: "Plain text secrets found; the values are not shown.",
+		Run:            builder.MustBuild(),
+	}
+	checks.MustRegisterCheck(check)
+}
+
+// scanners scan the file types which often contain secrets.
+var scanners = []struct {
+	fileType bundle.FileTypeName
+	scan     func([]byte) ([]secrets.Finding, error)
+}{
+	{"user-config", secrets.ScanYAML},
+	{"expanded-config", secrets.ScanJSON},
+	{"marathon-apps", secrets.ScanJSON},
+	{"mesos-master-environ", scanEnviron},
+	{"mesos-agent-environ", scanEnviron},
+}
+
+func scanEnviron(data []byte) ([]secrets.Finding, error) {
+	return secrets.ScanEnviron(data), nil
+}
+
+func check(host bundle.Host) checks.Result {
+	var found []string
+	var evidence []bundle.FileTypeName
+	scanned := 0
+	partial := false
+	for _, s := range scanners {
+		t, err := bundle.GetFileType(s.fileType)
+		if err != nil {
+			return checks.Result{Status: checks.SUndefined, Value: err}
+		}
+		if !t.ExistsOn(host.Type) {
+			continue
+		}
+		findings, err := scan(host, s.fileType, s.scan, &partial)
+		if errors.Is(err, bundle.ErrFileNotFound) {
+			continue
+		}
+		if err != nil {
+			return checks.Result{Status: checks.SUndefined, Value: err, Partial: partial}
+		}
+		scanned++
+		if len(findings) > 0 {
+			evidence = append(evidence, s.fileType)
+		}
+		for _, f := range findings {
+			found = append(found, fmt.Sprintf("%v: %v (%v)", s.fileType, f.Name, f.Kind))
+		}
+	}
+	if scanned == 0 {
+		// Any of the scanned files is enough, so the check cannot use
+		// checks.Check.Requires; the check is skipped if all hosts are.
+		return checks.Result{
+			Status: checks.SSkipped,
+			Value:  "Skipped: none of the files which might contain secrets are found.",
+		}
+	}
+	if len(found) > 0 {
+		return checks.Result{
+			Status:   checks.SProblem,
+			Value:    strings.Join(found, "\n"),
+			Partial:  partial,
+			Evidence: evidence,
+		}
+	}
+	return checks.Result{
+		Status:  checks.SOK,
+		Value:   fmt.Sprintf("No secrets found in %v file(s).", scanned),
+		Partial: partial,
+	}
+}
+
+func scan(host bundle.Host, typeName bundle.FileTypeName,
+	scan func([]byte) ([]secrets.Finding, error), partial *bool) ([]secrets.Finding, error) {
+	f, err := host.OpenFile(typeName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Partial(f) != nil {
+		*partial = true
+	}
+	findings, err := scan(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %v: %w", f.Name(), err)
+	}
+	return findings, nil
+}
@@ -0,0 +1,47 @@
+package plaintextsecrets
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func TestProblem(t *testing.T) {
+	b, err := bundle.New("test_bundles/problem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := checks.GetCheck("plaintext-secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := c.Run(b)
+	if results.Status() != checks.SProblem {
+		t.Fatalf("Expected Status = PROBLEM, observed %v: %v", results.Status(), results)
+	}
+	problems := results.Problems()
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 problems, observed %v", problems)
+	}
+	values := fmt.Sprint(problems[0].Value, problems[1].Value)
+	for _, expected := range []string{
+		"user-config: superuser_password_hash",
+		"marathon-apps: apps[0].env.AWS_SECRET_ACCESS_KEY",
+		"mesos-agent-environ: MESOS_ZK",
+	} {
+		if !strings.Contains(values, expected) {
+			t.Errorf("Expected %q in the results, observed %v", expected, values)
+		}
+	}
+	for _, secret := range []string{"hunter2", "s3cr3t", "AKIA", "$6$"} {
+		if strings.Contains(values, secret) {
+			t.Errorf("Expected secret %q not to be echoed, observed %v", secret, values)
+		}
+	}
+	if strings.Contains(values, "ssh_key_path") || strings.Contains(values, "image") {
+		t.Errorf("Expected no false positives, observed %v", values)
+	}
+}
@@ -16,7 +16,7 @@ func TestMesosLeaderFindsLeader(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	expected := bundle.IP("10.0.5.2")
+	expected := bundle.ParseIP("10.0.5.2")
 	if expected != leader.IP() {
 		t.Fatalf("Expected %v, observed %v", expected, leader.IP())
 	}
@@ -26,6 +26,6 @@ func NewMesosMaster(m bundle.Host) (MesosMaster, error) {
 	}
 	master.Host = m
 	// All masters will become the same ID
-	master.IsLeader = m.IP == bundle.IP(state.Hostname)
+	master.IsLeader = m.IP == bundle.ParseIP(state.Hostname)
 	return master, nil
 }
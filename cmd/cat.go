@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/tools/files"
+)
+
+func hostSelector(cmd *cobra.Command) files.Selector {
+	var s files.Selector
+	var err error
+	if s.Hosts, err = cmd.Flags().GetStringArray("host"); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	roles, err := cmd.Flags().GetStringArray("role")
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	s.Hosts = append(s.Hosts, roles...)
+	if s.Leader, err = cmd.Flags().GetBool("leader"); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	return s
+}
+
+func addHostSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("host", nil, "IP address or hostname of the host; repeat to select several")
+	cmd.Flags().StringArray("role", nil, "host type: root, master, agent, public-agent, control-plane, or worker; repeat to select several")
+	cmd.Flags().Bool("leader", false, "select the Mesos leader")
+}
+
+func catFile(cmd *cobra.Command, args []string) {
+	pretty, err := cmd.Flags().GetBool("pretty")
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	err = files.Cat(*currentBundle, bundle.FileTypeName(args[0]), hostSelector(cmd), pretty, os.Stdout)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func listFiles(cmd *cobra.Command, _ []string) {
+	entries, err := files.List(*currentBundle, hostSelector(cmd))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"File type", "Content type", "Hosts"})
+	table.SetAutoWrapText(false)
+	for _, e := range entries {
+		hosts := make([]string, 0, len(e.Hosts))
+		for _, h := range e.Hosts {
+			if h.IP.IsZero() {
+				hosts = append(hosts, string(h.Type))
+			} else {
+				hosts = append(hosts, h.IP.String())
+			}
+		}
+		table.Append([]string{string(e.FileType.Name), string(e.FileType.ContentType),
+			wordwrap.WrapString(strings.Join(hosts, " "), 60)})
+	}
+	table.Render()
+}
+
+func init() {
+	var catCmd = &cobra.Command{
+		Use:   "cat <file type>",
+		Short: "Prints a bundle file by its file type name",
+		Long: "Finds the file of the given type, e.g. mesos-master-state, and prints it" +
+			" decompressed. Without host selectors it prints the file from the bundle" +
+			" root or from all the hosts which have it; each file is preceded by" +
+			" a header if there are several. Use `bun tool ls` to see which file" +
+			" types are in the bundle.",
+		Example: "  bun tool cat mesos-master-state --leader --pretty\n" +
+			"  bun tool cat dmesg-log --host 10.0.0.2",
+		Args:   cobra.ExactArgs(1),
+		Run:    catFile,
+		PreRun: preRun,
+	}
+	addHostSelectorFlags(catCmd)
+	catCmd.Flags().Bool("pretty", false, "indent JSON files")
+	toolCmd.AddCommand(catCmd)
+
+	var lsCmd = &cobra.Command{
+		Use:    "ls",
+		Short:  "Lists the file types found on each host",
+		Long:   "Lists the known file types which are present in the bundle and the hosts which have them.",
+		Run:    listFiles,
+		PreRun: preRun,
+	}
+	addHostSelectorFlags(lsCmd)
+	toolCmd.AddCommand(lsCmd)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+// explainWidth is the maximum width of the bun explain output.
+const explainWidth = 100
+
+func runExplain(_ *cobra.Command, args []string) {
+	c, err := checks.GetCheck(args[0])
+	if err != nil {
+		fmt.Printf("%v; run `bun check --help` to list the checks\n", err.Error())
+		os.Exit(1)
+	}
+	fmt.Print(explanation(c))
+}
+
+// explanation renders the documentation of the check.
+func explanation(c checks.Check) string {
+	var b strings.Builder
+	b.WriteString(wrapIndent(c.Name+": "+c.Description, "", "  "))
+	if c.Flavor != "" {
+		fmt.Fprintf(&b, "Flavor: %v\n", c.Flavor)
+	}
+	if len(c.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %v\n", strings.Join(c.Tags, ", "))
+	}
+	writeParagraph(&b, "Background", c.Doc.Background)
+	writeList(&b, "Symptoms", c.Doc.Symptoms, false)
+	writeList(&b, "Files inspected", filesInspected(c), false)
+	writeList(&b, "Affected versions", c.Doc.AffectedVersions, false)
+	params := make([]string, 0, len(c.Params))
+	for _, p := range c.Params {
+		params = append(params, fmt.Sprintf("%v (default %v): %v", p.Name, p.Default, p.Description))
+	}
+	writeList(&b, "Parameters", params, false)
+	writeParagraph(&b, "Cure", c.Cure)
+	writeList(&b, "Remediation", c.Doc.Remediation, true)
+	links := make([]string, 0, len(c.Doc.Links))
+	for _, l := range c.Doc.Links {
+		links = append(links, l.String())
+	}
+	writeList(&b, "Links", links, false)
+	return b.String()
+}
+
+// filesInspected describes the files the check inspects with their paths
+// and the directories they are searched in.
+func filesInspected(c checks.Check) []string {
+	files := c.Files()
+	descriptions := make([]string, 0, len(files))
+	for _, name := range files {
+		t, err := bundle.GetFileType(name)
+		if err != nil {
+			descriptions = append(descriptions, string(name))
+			continue
+		}
+		dirTypes := make([]string, 0, len(t.DirTypes))
+		for _, d := range t.DirTypes {
+			dirTypes = append(dirTypes, string(d))
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%v: %v on %v",
+			name, strings.Join(t.Paths, ", "), strings.Join(dirTypes, ", ")))
+	}
+	return descriptions
+}
+
+func writeParagraph(b *strings.Builder, title string, text string) {
+	if text == "" {
+		return
+	}
+	b.WriteString("\n" + title + ":\n")
+	b.WriteString(wrapIndent(text, "  ", "  "))
+}
+
+func writeList(b *strings.Builder, title string, items []string, numbered bool) {
+	if len(items) == 0 {
+		return
+	}
+	b.WriteString("\n" + title + ":\n")
+	for i, item := range items {
+		marker := "- "
+		if numbered {
+			marker = strconv.Itoa(i+1) + ". "
+		}
+		b.WriteString(wrapIndent(item, "  "+marker, strings.Repeat(" ", 2+len(marker))))
+	}
+}
+
+// wrapIndent wraps the text to explainWidth and prefixes the first line with
+// first and the other lines with rest. Line breaks of the text are kept, so
+// commands in remediation steps stay intact.
+func wrapIndent(text string, first string, rest string) string {
+	lines := strings.Split(wordwrap.WrapString(text, uint(explainWidth-len(rest))), "\n")
+	for i := range lines {
+		if i == 0 {
+			lines[i] = first + lines[i]
+		} else {
+			lines[i] = rest + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func init() {
+	var explainCmd = &cobra.Command{
+		Use:   "explain <check>",
+		Short: "Explains a check and how to fix the problems it detects",
+		Long: "Shows the documentation of the check: the background, the symptoms, the files inspected," +
+			" the affected versions, the remediation steps, and the links to the KB articles and issues.",
+		Example: "  bun explain mesos-9868",
+		Args:    cobra.ExactArgs(1),
+		Run:     runExplain,
+		PreRun: func(*cobra.Command, []string) {
+			loadChecks()
+		},
+	}
+	rootCmd.AddCommand(explainCmd)
+}
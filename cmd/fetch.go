@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/tools/fetch"
+)
+
+func fetchBundle(cmd *cobra.Command, _ []string) {
+	flags := cmd.Flags()
+	clusterURL, _ := flags.GetString("url")
+	token, _ := flags.GetString("token")
+	id, _ := flags.GetString("bundle-id")
+	dir, _ := flags.GetString("dir")
+	insecure, _ := flags.GetBool("insecure")
+	pollInterval, _ := flags.GetDuration("poll-interval")
+	f, err := fetch.New(fetch.Options{
+		URL:          clusterURL,
+		Token:        clusterToken(token),
+		Client:       clusterClient(insecure),
+		PollInterval: pollInterval,
+		Progress:     os.Stdout,
+	})
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	archive, err := f.Fetch(ctx, id, dir)
+	if err != nil {
+		fmt.Printf("Cannot fetch the bundle: %v\n", err.Error())
+		os.Exit(1)
+	}
+	extracted := strings.TrimSuffix(archive, ".zip")
+	fmt.Printf("Extracting the bundle to %v\n", extracted)
+	if err := bundle.Extract(archive, extracted); err != nil {
+		fmt.Printf("Cannot extract the bundle: %v\n", err.Error())
+		os.Exit(1)
+	}
+	b, err := bundle.OpenExtracted(extracted)
+	if err != nil {
+		fmt.Printf("Cannot open a bundle: %v\n", err.Error())
+		os.Exit(1)
+	}
+	currentBundle = &b
+	runCheck(cmd, nil)
+}
+
+// clusterToken returns the token or, if it's empty, $DCOS_ACS_TOKEN.
+func clusterToken(token string) string {
+	if token == "" {
+		return os.Getenv("DCOS_ACS_TOKEN")
+	}
+	return token
+}
+
+// clusterClient returns the HTTP client for the cluster APIs.
+func clusterClient(insecure bool) *http.Client {
+	if !insecure {
+		return http.DefaultClient
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return &http.Client{Transport: transport}
+}
+
+func init() {
+	var fetchCmd = &cobra.Command{
+		Use:   "fetch",
+		Short: "Downloads a diagnostics bundle from a DC/OS cluster and checks it",
+		Long: "Creates a diagnostics bundle of all the masters and agents via the dcos-diagnostics API," +
+			" waits until the cluster finishes it, downloads and extracts it to --dir, and runs all" +
+			" the checks. An interrupted download resumes when the command is run again with the" +
+			" same --bundle-id, which is printed when the bundle is created.",
+		Example: "  bun fetch --url https://dcos.example.com --token $(dcos config show core.dcos_acs_token)",
+		Run:     fetchBundle,
+		PreRun: func(*cobra.Command, []string) {
+			loadChecks()
+		},
+	}
+	fetchCmd.Flags().String("url", "", "URL of the DC/OS cluster")
+	fetchCmd.Flags().String("token", "", "DC/OS authentication token (default $DCOS_ACS_TOKEN)")
+	fetchCmd.Flags().String("bundle-id", "", "ID of an existing bundle to download instead of creating a new one")
+	fetchCmd.Flags().String("dir", ".", "directory to download and extract the bundle to")
+	fetchCmd.Flags().Bool("insecure", false, "do not verify the TLS certificate of the cluster")
+	fetchCmd.Flags().Duration("poll-interval", 5*time.Second, "interval of the bundle status requests")
+	_ = fetchCmd.MarkFlagRequired("url")
+	rootCmd.AddCommand(fetchCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/tools/grep"
+)
+
+var grepOptions grep.Options
+
+func grepBundle(cmd *cobra.Command, args []string) {
+	grepOptions.Pattern = args[0]
+	contentTypes, err := cmd.Flags().GetStringArray("content-type")
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(2)
+	}
+	for _, ct := range contentTypes {
+		grepOptions.ContentTypes = append(grepOptions.ContentTypes, bundle.ContentType(ct))
+	}
+	if context, err := cmd.Flags().GetInt("context"); err == nil && context > 0 {
+		if !cmd.Flags().Changed("before-context") {
+			grepOptions.Before = context
+		}
+		if !cmd.Flags().Changed("after-context") {
+			grepOptions.After = context
+		}
+	}
+	result, err := grep.Grep(currentBundle, grepOptions, os.Stdout)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+	for _, p := range result.Partial {
+		_, _ = fmt.Fprintf(os.Stderr, "bun: %v was read partially\n", p)
+	}
+	for _, e := range result.Errors {
+		_, _ = fmt.Fprintf(os.Stderr, "bun: %v\n", e)
+	}
+	switch {
+	case len(result.Errors) > 0:
+		os.Exit(2)
+	case result.Matches == 0:
+		os.Exit(1)
+	}
+}
+
+func init() {
+	var grepCmd = &cobra.Command{
+		Use:   "grep <pattern>",
+		Short: "Searches bundle files selected by file type and host",
+		Long: "Searches the files of the given file types, including compressed ones," +
+			" on the given hosts and prints the matching lines as" +
+			" host:filetype:line:text. The pattern is a regular expression in" +
+			" the RE2 syntax. Exits with the status 1 if nothing is found and 2" +
+			" if an error occurs.",
+		Example: "  bun tool grep -t 'mesos-*-log' --host agent -C 2 'OOM'\n" +
+			"  bun tool grep --content-type journal -c -F 'Failed to'",
+		Args:   cobra.ExactArgs(1),
+		Run:    grepBundle,
+		PreRun: preRun,
+	}
+	grepCmd.Flags().StringArrayVarP(&grepOptions.FileTypes, "type", "t", nil,
+		"file type name or glob pattern, e.g. mesos-*-log; repeat to search several")
+	grepCmd.Flags().StringArray("content-type", nil,
+		"search only file types with this content type: JSON, journal, dmesg, output, or other")
+	grepCmd.Flags().StringArrayVar(&grepOptions.Hosts, "host", nil,
+		"IP address, hostname, or host type (root, master, agent, public-agent, control-plane, worker); repeat to search several")
+	grepCmd.Flags().BoolVarP(&grepOptions.Fixed, "fixed-strings", "F", false, "interpret the pattern as a fixed string")
+	grepCmd.Flags().BoolVarP(&grepOptions.IgnoreCase, "ignore-case", "i", false, "ignore case distinctions")
+	grepCmd.Flags().BoolVarP(&grepOptions.Count, "count", "c", false, "print only the number of matching lines per file")
+	grepCmd.Flags().IntVarP(&grepOptions.Before, "before-context", "B", 0, "print this number of lines before matches")
+	grepCmd.Flags().IntVarP(&grepOptions.After, "after-context", "A", 0, "print this number of lines after matches")
+	grepCmd.Flags().IntP("context", "C", 0, "print this number of lines before and after matches")
+	toolCmd.AddCommand(grepCmd)
+}
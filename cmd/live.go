@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/bundle/live"
+)
+
+func runLive(cmd *cobra.Command, args []string) {
+	flags := cmd.Flags()
+	clusterURL, _ := flags.GetString("url")
+	token, _ := flags.GetString("token")
+	insecure, _ := flags.GetBool("insecure")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	b, err := live.New(ctx, live.Options{
+		URL:    clusterURL,
+		Token:  clusterToken(token),
+		Client: clusterClient(insecure),
+	})
+	if err != nil {
+		fmt.Printf("Cannot connect to the cluster: %v\n", err.Error())
+		os.Exit(1)
+	}
+	currentBundle = &b
+	runChecks(args)
+}
+
+func liveFileTypes() []string {
+	names := make([]string, 0, len(live.Endpoints))
+	for name := range live.Endpoints {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	var liveCmd = &cobra.Command{
+		Use:   "live [check...]",
+		Short: "Runs the checks against a live DC/OS cluster",
+		Long: "Reads the JSON file types from the cluster APIs instead of a bundle and runs the given" +
+			" checks, or all of them if none are given. Only the checks which read the following" +
+			" file types of the Mesos leader or the bundle root can produce results:\n  " +
+			fmt.Sprint(liveFileTypes()),
+		Example: "  bun live --url https://dcos.example.com marathon-deployments",
+		Run:     runLive,
+		PreRun: func(*cobra.Command, []string) {
+			loadChecks()
+		},
+	}
+	liveCmd.Flags().String("url", "", "URL of the DC/OS cluster")
+	liveCmd.Flags().String("token", "", "DC/OS authentication token (default $DCOS_ACS_TOKEN)")
+	liveCmd.Flags().Bool("insecure", false, "do not verify the TLS certificate of the cluster")
+	_ = liveCmd.MarkFlagRequired("url")
+	rootCmd.AddCommand(liveCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/mitchellh/go-wordwrap"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/olekukonko/tablewriter"
 
 	"github.com/mesosphere/bun/v2/checks"
+	"github.com/mesosphere/bun/v2/runner"
 )
 
 // MsgErr is a standard message used in the check summary when errors
@@ -44,9 +46,18 @@ func printReport(c checks.Check, r checks.Results, verbose bool) {
 			summary = "Couldn't check some hosts because of the error(s)." +
 				" Please find the details below."
 		}
+	case checks.SSkipped:
+		if !verbose {
+			return
+		}
+		status = au.Bold(au.Cyan("[" + r.Status() + "]")).String()
+		summary = "The bundle doesn't contain the files the check needs."
 	default:
 		panic("Unknown status: " + r.Status())
 	}
+	if r.Partial() {
+		summary += "\n" + "Some files were read partially, so the results might be inaccurate."
+	}
 	var data tableData = make([][]string, 0, len(r)+5)
 	data.appendBulk([][]string{
 		{au.Bold("Check").String(), c.Name},
@@ -54,11 +65,21 @@ func printReport(c checks.Check, r checks.Results, verbose bool) {
 		{au.Bold("Description").String(), c.Description},
 	})
 	if r.Status() == checks.SProblem {
-		data.append([]string{au.Bold("Cure").String(), c.Cure})
+		cure := c.Cure
+		if !c.Doc.IsEmpty() {
+			cure += "\n" + "See `bun explain " + c.Name + "` for the remediation steps and links."
+		}
+		data.append([]string{au.Bold("Cure").String(), cure})
 	}
 	data.append([]string{au.Bold("Summary").String(), summary})
+	if len(c.Params) > 0 {
+		data.append([]string{au.Bold("Parameters").String(), paramsString(c.Params)})
+	}
 	data.appendBulk(resultsData(r.Problems()))
 	data.appendBulk(resultsData(r.Undefined()))
+	if r.Status() == checks.SSkipped {
+		data.appendBulk(resultsData(r.Skipped()))
+	}
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetAutoMergeCells(true)
 	table.SetRowLine(true)
@@ -68,6 +89,19 @@ func printReport(c checks.Check, r checks.Results, verbose bool) {
 	fmt.Println()
 }
 
+// paramsString renders effective values of the check parameters.
+func paramsString(params checks.Params) string {
+	lines := make([]string, 0, len(params))
+	for _, p := range params {
+		line := fmt.Sprintf("%v = %v", p.Name, p.Value())
+		if p.IsSet() {
+			line += fmt.Sprintf(" (default %v)", p.Default)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func useColors() bool {
 	return !outputRedirectedToFile() && !noColor
 }
@@ -84,13 +118,22 @@ func resultsData(results checks.Results) [][]string {
 			leftColumn = au.Bold(au.Green("[OK]")).String()
 		case checks.SUndefined:
 			leftColumn = au.Bold(au.Yellow("[U]")).String()
+		case checks.SSkipped:
+			leftColumn = au.Bold(au.Cyan("[S]")).String()
 		default:
 			panic("Unknown status: " + result.Status)
 		}
-		if result.Host.IP != "" {
+		if !result.Host.IP.IsZero() {
 			leftColumn += fmt.Sprintf(" %v %v", result.Host.Type, result.Host.IP)
 		}
-		data = append(data, []string{leftColumn, fmt.Sprintf("%v", result.Value)})
+		if result.Partial {
+			leftColumn += " (partial data)"
+		}
+		value := fmt.Sprintf("%v", result.Value)
+		if p, ok := result.Value.(*checks.PanicError); ok && verbose {
+			value += "\n" + string(p.Stack)
+		}
+		data = append(data, []string{leftColumn, value})
 	}
 	return data
 }
@@ -113,13 +156,14 @@ func wrap(row []string) []string {
 	}
 }
 
-func printSummary(results []checks.Results) {
+func printSummary(report runner.Report) {
 	au := aurora.NewAurora(useColors())
-	total := len(results)
+	total := len(report.Checks)
 	nP := 0
 	nOK := 0
 	nU := 0
-	for _, r := range results {
+	var skipped []string
+	for _, r := range report.Checks {
 		switch r.Status() {
 		case checks.SProblem:
 			nP++
@@ -127,16 +171,23 @@ func printSummary(results []checks.Results) {
 			nOK++
 		case checks.SUndefined:
 			nU++
+		case checks.SSkipped:
+			skipped = append(skipped, r.Check.Name)
 		default:
 			panic("Unknown status " + r.Status())
 		}
 	}
-	var data tableData = make([][]string, 0, 4)
+	var data tableData = make([][]string, 0, 5)
 	data.appendBulk([][]string{
 		{au.Bold("Problem").String(), strconv.Itoa(nP)},
 		{au.Bold("Undefined").String(), strconv.Itoa(nU)},
 		{au.Bold("OK").String(), strconv.Itoa(nOK)},
+		{au.Bold("Skipped").String(), strconv.Itoa(len(skipped))},
 	})
+	if len(skipped) > 0 {
+		data.append([]string{au.Bold("Skipped checks").String(),
+			wordwrap.WrapString(strings.Join(skipped, ", "), 60)})
+	}
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetHeader([]string{"Summary", ""})
 	table.SetFooter([]string{"Total", strconv.Itoa(total)})
@@ -1,18 +1,27 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"sort"
+	"time"
 
 	"github.com/mesosphere/bun/v2/bundle"
 	"github.com/mesosphere/bun/v2/checks"
+	"github.com/mesosphere/bun/v2/checks/plugin"
+	"github.com/mesosphere/bun/v2/checks/script"
+	"github.com/mesosphere/bun/v2/runner"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	bundlePath    string
+	bundlePaths   []string
+	scriptsDir    string
+	pluginsDir    string
+	pluginTimeout time.Duration
+	configPath    string
+	settings      []string
 	currentBundle *bundle.Bundle
 	verbose       = false
 	noColor       = false
@@ -37,8 +46,12 @@ var rootCmd = &cobra.Command{
 }
 
 var checkCmd = &cobra.Command{
-	Use:   "check",
+	Use:   "check [name...]",
 	Short: "Run specific check",
+	Long: "Runs the checks with the names. The built-in checks have their own sub-commands;\n" +
+		"custom checks from --scripts-dir and --plugins-dir are found by the name when Bun runs.",
+	PreRun: preRun,
+	Run:    runNamedChecks,
 }
 
 func init() {
@@ -47,30 +60,44 @@ func init() {
 		fmt.Printf("Error while detecting a working directory: %v\n", err.Error())
 		os.Exit(1)
 	}
-	rootCmd.PersistentFlags().StringVarP(&bundlePath, "path", "p", wd,
-		"path to the bundle directory")
+	rootCmd.PersistentFlags().StringArrayVarP(&bundlePaths, "path", "p", []string{wd},
+		"path to the bundle directory; repeat to merge several bundles or host directories")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false,
 		"print detailed output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "do not show ASCII colors")
-	checks.RegisterSearchChecks()
+	rootCmd.PersistentFlags().StringVar(&scriptsDir, "scripts-dir", "",
+		"path to the directory with custom Starlark (*.star) checks")
+	rootCmd.PersistentFlags().StringVar(&pluginsDir, "plugins-dir", "",
+		"path to the directory with executable check plugins")
+	rootCmd.PersistentFlags().DurationVar(&pluginTimeout, "plugin-timeout", plugin.DefaultTimeout,
+		"maximum execution time of a plugin")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "",
+		"path to the YAML file with check parameters")
+	rootCmd.PersistentFlags().StringArrayVar(&settings, "set", nil,
+		"override a check parameter, e.g. --set marathon-deployments.max-deployments=40")
 	// Adding registered checks as commands.
 	for _, c := range checks.Checks() {
 		run := func(cmd *cobra.Command, args []string) {
-			check := checks.GetCheck(cmd.Use)
-			results := check.Run(*currentBundle)
-			printReport(check, results, true)
-			return
+			opts := runner.Options{
+				Checks: []string{cmd.Use},
+				OnCheckDone: func(r runner.CheckReport) {
+					printReport(r.Check, r.Results, true)
+				},
+			}
+			if _, err := runner.RunBundle(context.Background(), *currentBundle, opts); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
 		}
 		var cmd = &cobra.Command{
 			Use:    c.Name,
 			Short:  c.Description,
-			Long:   c.Description,
+			Long:   explanation(c),
 			PreRun: preRun,
 			Run:    run,
 		}
 		checkCmd.AddCommand(cmd)
 		checkCmd.ValidArgs = append(rootCmd.ValidArgs, cmd.Use)
-		checkCmd.PreRun = preRun
 	}
 	rootCmd.AddCommand(checkCmd)
 }
@@ -79,7 +106,8 @@ func preRun(*cobra.Command, []string) {
 	if currentBundle != nil {
 		return
 	}
-	b, err := bundle.New(bundlePath)
+	loadChecks()
+	b, err := bundle.New(bundlePaths...)
 	if err != nil {
 		fmt.Printf("Cannot open a bundle: %v\n", err.Error())
 		os.Exit(1)
@@ -87,23 +115,68 @@ func preRun(*cobra.Command, []string) {
 	currentBundle = &b
 }
 
-func runCheck(_ *cobra.Command, _ []string) {
-	c := checks.Checks()
-	sort.Slice(c, func(i, j int) bool {
-		return c[i].Name < c[j].Name
-	})
-	ok := true
-	allResults := make([]checks.Results, 0, len(c))
-	for _, check := range c {
-		results := check.Run(*currentBundle)
-		allResults = append(allResults, results)
-		printReport(check, results, verbose)
-		if results.Status() != checks.SOK {
-			ok = false
+// loadChecks registers the custom checks and applies the check parameters.
+func loadChecks() {
+	if err := runner.BuiltinChecksError(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Some checks are skipped: %v\n", err.Error())
+	}
+	if scriptsDir != "" {
+		if err := script.RegisterChecks(scriptsDir); err != nil {
+			fmt.Printf("Cannot load custom checks: %v\n", err.Error())
+			os.Exit(1)
 		}
 	}
-	printSummary(allResults)
-	if !ok {
+	if pluginsDir != "" {
+		if err := plugin.RegisterPlugins(pluginsDir, pluginTimeout); err != nil {
+			fmt.Printf("Cannot load plugins: %v\n", err.Error())
+			os.Exit(1)
+		}
+	}
+	if configPath != "" {
+		if err := checks.LoadConfig(configPath); err != nil {
+			fmt.Printf("Cannot load config: %v\n", err.Error())
+			os.Exit(1)
+		}
+	}
+	for _, s := range settings {
+		if err := checks.ParseSetting(s); err != nil {
+			fmt.Printf("Cannot set check parameter: %v\n", err.Error())
+			os.Exit(1)
+		}
+	}
+}
+
+func runCheck(_ *cobra.Command, _ []string) {
+	runChecks(nil)
+}
+
+// runNamedChecks runs the checks with the names given as arguments. The custom
+// checks are registered in preRun, after the sub-commands are built, so they
+// are looked up in the registry here.
+func runNamedChecks(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		_ = cmd.Help()
+		return
+	}
+	runChecks(args)
+}
+
+// runChecks runs the checks with the names, or all of them if names is empty,
+// and prints the summary.
+func runChecks(names []string) {
+	opts := runner.Options{
+		Checks: names,
+		OnCheckDone: func(r runner.CheckReport) {
+			printReport(r.Check, r.Results, verbose)
+		},
+	}
+	report, err := runner.RunBundle(context.Background(), *currentBundle, opts)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	printSummary(report)
+	if !report.OK() {
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckRunsScriptCheck(t *testing.T) {
+	rootCmd.SetArgs([]string{"-v", "-p", "../checks/script/test_bundles/ok",
+		"--scripts-dir", "../checks/script/test_scripts/ok", "check", "script-scan"})
+	out := captureStdout(t, func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "script-scan") || strings.Contains(out, "Usage:") {
+		t.Errorf("Expected the report of script-scan, observed:\n%v", out)
+	}
+}
+
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&out, r)
+		close(done)
+	}()
+	f()
+	_ = w.Close()
+	<-done
+	return out.String()
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/secrets"
+	"github.com/mesosphere/bun/v2/tools/sanitize"
+)
+
+func sanitizeBundle(cmd *cobra.Command, _ []string) {
+	out, err := cmd.Flags().GetString("out")
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	keyPath, err := cmd.Flags().GetString("key")
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	if keyPath == "" {
+		keyPath = filepath.Clean(out) + ".key.json"
+	}
+	key, report, err := sanitize.Sanitize(currentBundle, out)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	k, err := json.MarshalIndent(key, "", "  ")
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	if err := ioutil.WriteFile(keyPath, k, 0600); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	summary := tablewriter.NewWriter(os.Stdout)
+	summary.SetHeader([]string{"Summary", ""})
+	summary.Append([]string{"Files written", strconv.Itoa(report.Files)})
+	summary.Append([]string{"IP addresses replaced", strconv.Itoa(len(key.IPs))})
+	summary.Append([]string{"Hostnames replaced", strconv.Itoa(len(key.Hostnames))})
+	kinds := make([]string, 0, len(report.Secrets))
+	for kind := range report.Secrets {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		summary.Append([]string{"Masked: " + kind, strconv.Itoa(report.Secrets[secrets.Kind(kind)])})
+	}
+	summary.Render()
+	for _, p := range report.Partial {
+		fmt.Printf("%v was read partially.\n", p)
+	}
+	fmt.Printf("Sanitized bundle: %v\n", out)
+	fmt.Printf("Key to decode the pseudonyms: %v\n", keyPath)
+	fmt.Println("Don't share the key together with the sanitized bundle.")
+}
+
+func init() {
+	var sanitizeCmd = &cobra.Command{
+		Use:   "sanitize",
+		Short: "Writes a redacted copy of the bundle",
+		Long: "Writes a copy of the bundle to the --out directory with passwords, tokens," +
+			" and private keys masked, and IP addresses and hostnames consistently" +
+			" replaced with pseudonyms in the files and the directory names, so" +
+			" the checks still work. The mapping of the pseudonyms to the original" +
+			" values is written to a separate key file.",
+		Run:    sanitizeBundle,
+		PreRun: preRun,
+	}
+	sanitizeCmd.Flags().StringP("out", "o", "", "Directory to write the sanitized bundle to; it must not exist or be empty")
+	sanitizeCmd.Flags().String("key", "", "File to write the pseudonym key to (default <out>.key.json)")
+	_ = sanitizeCmd.MarkFlagRequired("out")
+	toolCmd.AddCommand(sanitizeCmd)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/server"
+)
+
+func serve(cmd *cobra.Command, _ []string) {
+	flags := cmd.Flags()
+	listen, _ := flags.GetString("listen")
+	dataDir, _ := flags.GetString("data-dir")
+	allowLocalPaths, _ := flags.GetBool("allow-local-paths")
+	workers, _ := flags.GetInt("workers")
+	maxUploadSize, _ := flags.GetInt64("max-upload-size")
+	maxExtractedSize, _ := flags.GetInt64("max-extracted-size")
+	if dataDir == "" {
+		dataDir = filepath.Join(os.TempDir(), "bun-serve")
+	}
+	s, err := server.New(server.Options{
+		DataDir:          dataDir,
+		AllowLocalPaths:  allowLocalPaths,
+		Workers:          workers,
+		MaxUploadSize:    maxUploadSize << 20,
+		MaxExtractedSize: maxExtractedSize << 20,
+	})
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if cmd.Flags().Changed("path") {
+		for _, p := range bundlePaths {
+			if _, err := s.Submit(p); err != nil {
+				fmt.Printf("Cannot submit the bundle %v: %v\n", p, err.Error())
+				os.Exit(1)
+			}
+		}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("Serving on http://%v\n", listen)
+	if err := s.Serve(ctx, listen); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func init() {
+	var serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Runs an HTTP server for bundle triage",
+		Long: "Serves a REST API and a web UI which accept bundle archives (zip or tar)," +
+			" run the checks asynchronously, and show the results, the hosts, the file" +
+			" types, and the file contents. The bundles given with --path are" +
+			" submitted at the start; submitting other local paths via the API" +
+			" requires --allow-local-paths.\n\n" +
+			"Endpoints:\n" +
+			"  GET, POST   /api/bundles\n" +
+			"  GET, DELETE /api/bundles/{id}\n" +
+			"  GET         /api/bundles/{id}/checks\n" +
+			"  GET         /api/bundles/{id}/hosts\n" +
+			"  GET         /api/bundles/{id}/files[?host=...]\n" +
+			"  GET         /api/bundles/{id}/files/{file type}[?host=...&leader=true&pretty=true]\n" +
+			"  GET         /api/checks",
+		Run: serve,
+		PreRun: func(*cobra.Command, []string) {
+			loadChecks()
+		},
+	}
+	serveCmd.Flags().String("listen", "127.0.0.1:8080", "address to listen on")
+	serveCmd.Flags().String("data-dir", "", "directory to extract the uploaded bundles to (default <temp dir>/bun-serve)")
+	serveCmd.Flags().Bool("allow-local-paths", false, "allow submitting paths on the server filesystem via the API")
+	serveCmd.Flags().Int("workers", 2, "number of bundles checked concurrently")
+	serveCmd.Flags().Int64("max-upload-size", 0, "maximum size of an uploaded archive in MiB; unlimited if 0")
+	serveCmd.Flags().Int64("max-extracted-size", 0,
+		"maximum total size of the files extracted from an uploaded archive in MiB; unlimited if 0")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -21,7 +21,7 @@ var toolCmd = &cobra.Command{
 }
 
 func findFiles(cmd *cobra.Command, _ []string) {
-	fileTypes, err := files.FindFiles(bundlePath)
+	fileTypes, err := files.FindFiles(bundlePaths...)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, err.Error())
 		os.Exit(1)
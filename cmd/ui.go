@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/runner"
+	"github.com/mesosphere/bun/v2/ui"
+)
+
+func runUI(*cobra.Command, []string) {
+	fmt.Println("Running the checks...")
+	report, err := runner.RunBundle(context.Background(), *currentBundle, runner.Options{})
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if err := ui.Run(*currentBundle, report); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func init() {
+	var uiCmd = &cobra.Command{
+		Use:   "ui",
+		Short: "Explores the bundle in an interactive terminal UI",
+		Long: "Runs all the checks and shows their results, the files of each host," +
+			" and a file viewer with search. Selecting a check result opens" +
+			" the file the result is based on. Compressed files are decompressed" +
+			" transparently.",
+		Run:    runUI,
+		PreRun: preRun,
+	}
+	rootCmd.AddCommand(uiCmd)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/mesosphere/bun/v2/tools/validate"
+)
+
+func validateBundle(cmd *cobra.Command, _ []string) {
+	skipMissing, err := cmd.Flags().GetBool("skip-missing")
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	report, err := validate.Validate(currentBundle)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+	printValidationReport(report, skipMissing)
+	if len(report.MissingHosts) > 0 || len(report.Issues) > report.Count(validate.KMissing) {
+		os.Exit(1)
+	}
+}
+
+func printValidationReport(report validate.Report, skipMissing bool) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Host", "File type", "Problem", "Details"})
+	table.SetAutoWrapText(false)
+	table.SetAutoMergeCells(true)
+	table.SetRowLine(true)
+	for _, i := range report.Issues {
+		if skipMissing && i.Kind == validate.KMissing {
+			continue
+		}
+		host := string(i.DirType)
+		if !i.IP.IsZero() {
+			host += " " + i.IP.String()
+		}
+		details := i.Path
+		for _, root := range currentBundle.Paths() {
+			if rel, err := filepath.Rel(root, i.Path); err == nil && i.Path != "" && !strings.HasPrefix(rel, "..") {
+				details = rel
+				break
+			}
+		}
+		if i.Error != "" {
+			details += "\n" + i.Error
+		}
+		table.Append([]string{host, string(i.FileType), string(i.Kind), wordwrap.WrapString(details, 60)})
+	}
+	for _, h := range report.MissingHosts {
+		details := "Registered in Mesos as " + h.Hostname + " (" + h.ID + ")"
+		for _, line := range report.SummaryErrorsFor(h.IP) {
+			details += "\n" + line
+		}
+		table.Append([]string{"agent " + h.IP.String(), "", "host missing", wordwrap.WrapString(details, 60)})
+	}
+	if table.NumLines() > 0 {
+		table.Render()
+		fmt.Println()
+	}
+	if len(report.SummaryErrors) > 0 {
+		fmt.Println("Errors reported by the bundle creator (summary-errors-report):")
+		for _, line := range report.SummaryErrors {
+			fmt.Println("  " + line)
+		}
+		fmt.Println()
+	}
+	if !report.AgentsChecked {
+		fmt.Println("Couldn't read the list of the registered agents; missing hosts are not detected.")
+		fmt.Println()
+	}
+	summary := tablewriter.NewWriter(os.Stdout)
+	summary.SetHeader([]string{"Summary", ""})
+	summary.AppendBulk([][]string{
+		{"Missing files", strconv.Itoa(report.Count(validate.KMissing))},
+		{"Empty files", strconv.Itoa(report.Count(validate.KEmpty))},
+		{"Unreadable files", strconv.Itoa(report.Count(validate.KUnreadable))},
+		{"Corrupted files", strconv.Itoa(report.Count(validate.KCorrupt))},
+		{"Missing hosts", strconv.Itoa(len(report.MissingHosts))},
+	})
+	summary.Render()
+}
+
+func init() {
+	var validateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Validates bundle completeness and integrity",
+		Long: "Checks that all the known files are present on every host, and they are" +
+			" not empty or corrupted. Reports the agents registered in Mesos" +
+			" which are missing in the bundle. Exits with the status 1 if any" +
+			" problems other than missing files are found.",
+		Run:    validateBundle,
+		PreRun: preRun,
+	}
+	validateCmd.Flags().Bool("skip-missing", false, "Don't list missing files")
+	toolCmd.AddCommand(validateCmd)
+}
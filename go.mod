@@ -1,13 +1,31 @@
 module github.com/mesosphere/bun/v2
 
-go 1.16
+go 1.18
 
 require (
+	github.com/gdamore/tcell/v2 v2.4.1-0.20210905002822-f057f0a857a1
 	github.com/hako/durafmt v0.0.0-20200710122514-c0fb7b4da026
 	github.com/hashicorp/go-version v1.2.0
+	github.com/klauspost/compress v1.15.9
 	github.com/logrusorgru/aurora v0.0.0-20191017060258-dc85c304c434
 	github.com/mitchellh/go-wordwrap v1.0.0
 	github.com/olekukonko/tablewriter v0.0.3
+	github.com/pierrec/lz4/v4 v4.1.17
+	github.com/rivo/tview v0.0.0-20220307222120-9994674d60a8
 	github.com/spf13/cobra v0.0.5
+	github.com/ulikunitz/xz v0.5.11
+	go.starlark.net v0.0.0-20230525235612-a134d8f9ddca
 	gopkg.in/yaml.v2 v2.2.2
 )
+
+require (
+	github.com/gdamore/encoding v1.0.0 // indirect
+	github.com/inconshreveable/mousetrap v1.0.0 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
+	github.com/mattn/go-runewidth v0.0.13 // indirect
+	github.com/rivo/uniseg v0.2.0 // indirect
+	github.com/spf13/pflag v1.0.3 // indirect
+	golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
+	golang.org/x/term v0.0.0-20220526004731-065cf7ba2467 // indirect
+	golang.org/x/text v0.3.6 // indirect
+)
@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/bundle/bundletest"
+)
+
+// samples build the synthetic sample bundles for the checks which read files
+// too large or too many to keep in test_bundles. Their golden files are
+// golden/runner-sample-<name>.json.
+var samples = map[string]func(t *testing.T) bundle.Bundle{
+	"dcos-ok":      dcosOK,
+	"dcos-problem": dcosProblem,
+}
+
+// node describes the files of a sample host.
+type node struct {
+	cpus      int
+	memKB     int
+	diskKB    int
+	unhealthy []string
+	events    int
+	ips       []string // the IP addresses of the container c1
+	backend   string   // the back-end of the /app:80 VIP
+}
+
+func (n node) write(h *bundletest.HostBuilder, flags bundle.FileTypeName) {
+	var cpuinfo strings.Builder
+	for i := 0; i < n.cpus; i++ {
+		fmt.Fprintf(&cpuinfo, "processor\t: %v\nmodel name\t: Sample CPU\n\n", i)
+	}
+	h.File("cpuinfo", cpuinfo.String())
+	h.File("meminfo", fmt.Sprintf("MemTotal:       %v kB\nMemFree:        1000000 kB\n", n.memKB))
+	h.File("df", "Filesystem     1K-blocks    Used Available Use% Mounted on\n"+
+		"/dev/xvda1      52416492 8155440  44261052  16% /\n"+
+		fmt.Sprintf("/dev/xvdb      %v 1000000  %v   1%% /var/lib/mesos\n", n.diskKB, n.diskKB-1000000))
+	h.File(flags, `{"flags": {"work_dir": "/var/lib/mesos/slave", "runtime_dir": "/var/lib/mesos/run"}}`)
+	units := []string{`{"id": "dcos-mesos-master.service", "health": 0}`}
+	for _, u := range n.unhealthy {
+		units = append(units, fmt.Sprintf(`{"id": %q, "health": 1}`, u))
+	}
+	h.File("diagnostics-health", `{"units": [`+strings.Join(units, ", ")+`]}`)
+	events := make([]string, n.events)
+	for i := range events {
+		events[i] = `{"type": "DISPATCH"}`
+	}
+	h.File("mesos-processes", `[{"id": "slave(1)", "events": [`+strings.Join(events, ", ")+`]}]`)
+	h.File("vips", fmt.Sprintf(`[{"vip": "/app:80", "backend": [{"ip": %q, "port": 80}]}]`, n.backend))
+}
+
+func (n node) writeAgent(h *bundletest.HostBuilder, pending bool, overlayError string) {
+	n.write(h, "mesos-agent-flags")
+	quoted := make([]string, 0, len(n.ips))
+	for _, ip := range n.ips {
+		quoted = append(quoted, fmt.Sprintf(`{"ip_address": %q}`, ip))
+	}
+	h.File("mesos-agent-containers", `[{"container_id": "c1", "status": {"network_infos": [{"ip_addresses": [`+
+		strings.Join(quoted, ", ")+`]}]}}]`)
+	if pending {
+		h.File("mesos-agent-containerizer-debug", `{"pending": [{"operation": "mount", "args": {"target": "/var"}}]}`)
+	} else {
+		h.File("mesos-agent-containerizer-debug", `{"pending": []}`)
+	}
+	h.File("mesos-agent-overlay", `{"ip": "`+h.Host().IP.Address+`", "overlays": [{"info": {"name": "dcos"}, `+
+		`"state": {"status": "STATUS_OK", "error": "`+overlayError+`"}}]}`)
+}
+
+// master describes the files of a sample master in addition to the node ones.
+type master struct {
+	deployments int
+	offered     string // the offered resources of the marathon framework
+	slaves      string // the agents known to the leading master
+}
+
+func (n node) writeMaster(h *bundletest.HostBuilder, m master) {
+	n.write(h, "mesos-master-flags")
+	// All masters report the leader as the hostname.
+	h.File("mesos-master-state", `{"id": "m1", "hostname": "10.0.0.1", "slaves": [`+m.slaves+`], `+
+		`"frameworks": [{"id": "f1", "name": "marathon", "tasks": [`+
+		`{"name": "app", "state": "TASK_RUNNING", "statuses": [{"timestamp": 1, "container_status": `+
+		`{"container_id": {"value": "c1"}, "network_infos": [{"ip_addresses": [{"ip_address": "9.0.0.1"}]}]}}]}]}]}`)
+	h.File("mesos-master-frameworks", `{"frameworks": [{"id": "f1", "name": "marathon", "offered_resources": `+
+		m.offered+`}]}`)
+	h.File("marathon-deployments", "["+strings.TrimSuffix(strings.Repeat(`{"id": "d"}, `, m.deployments), ", ")+"]")
+}
+
+// dcosOK is a DC/OS cluster which meets the requirements and has no problems.
+func dcosOK(t *testing.T) bundle.Bundle {
+	b := bundletest.New(t)
+	m := node{cpus: 4, memKB: 32_000_000, diskKB: 130_000_000, backend: "9.0.0.1"}
+	for _, ip := range []string{"10.0.0.1", "10.0.0.3", "10.0.0.4"} {
+		m.writeMaster(b.Master(ip), master{deployments: 1, offered: `{}`,
+			slaves: `{"hostname": "10.0.0.2", "active": true}`})
+	}
+	node{cpus: 2, memKB: 16_000_000, diskKB: 70_000_000, ips: []string{"9.0.0.1"}, backend: "9.0.0.1"}.
+		writeAgent(b.Agent("10.0.0.2"), false, "")
+	return b.Build()
+}
+
+// dcosProblem is a DC/OS cluster with a problem for each of the checks.
+func dcosProblem(t *testing.T) bundle.Bundle {
+	b := bundletest.New(t)
+	node{cpus: 2, memKB: 8_000_000, diskKB: 50_000_000, unhealthy: []string{"dcos-marathon.service"}, events: 31,
+		backend: "9.0.0.1"}.writeMaster(b.Master("10.0.0.1"), master{deployments: 11, offered: `{"cpus": 1, "mem": 128}`,
+		slaves: `{"hostname": "10.0.0.2", "active": false}`})
+	node{cpus: 1, memKB: 8_000_000, diskKB: 30_000_000, ips: []string{"9.0.0.2"}, backend: "9.0.0.3"}.
+		writeAgent(b.Agent("10.0.0.2"), true, "Failed to create the network: network already exists")
+	return b.Build()
+}
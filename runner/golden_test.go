@@ -0,0 +1,211 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+// Run `go test ./runner -run TestGolden -update` to regenerate the golden
+// files after an intended change of the check results.
+var update = flag.Bool("update", false, "update the golden files")
+
+// corpusGlobs match the sample bundles which all the checks are run against:
+// the bundles of the check tests and the shared ones of the runner.
+var corpusGlobs = []string{
+	"../checks/*/test_bundles/*",
+	"../checks/*/*/test_bundles/*",
+	"test_bundles/*",
+}
+
+type goldenResult struct {
+	Status  checks.Status `json:"status"`
+	Host    string        `json:"host,omitempty"`
+	Value   string        `json:"value,omitempty"`
+	Partial bool          `json:"partial,omitempty"`
+}
+
+type goldenCheck struct {
+	Name    string         `json:"name"`
+	Status  checks.Status  `json:"status"`
+	Results []goldenResult `json:"results"`
+}
+
+// normalize converts the report to a form which doesn't depend on the
+// location of the bundle or the order of the results.
+func normalize(report Report) []goldenCheck {
+	root := report.Bundle.Path
+	golden := make([]goldenCheck, 0, len(report.Checks))
+	for _, c := range report.Checks {
+		g := goldenCheck{Name: c.Check.Name, Status: c.Status(), Results: make([]goldenResult, 0, len(c.Results))}
+		for _, r := range c.Results {
+			result := goldenResult{Status: r.Status, Partial: r.Partial}
+			if r.IsHostSet() {
+				result.Host = fmt.Sprintf("%v %v", r.Host.Type, r.Host.IP)
+			}
+			if r.Value != nil {
+				result.Value = strings.ReplaceAll(fmt.Sprint(r.Value), root, "<bundle>")
+			}
+			g.Results = append(g.Results, result)
+		}
+		sort.SliceStable(g.Results, func(i, j int) bool {
+			a, b := g.Results[i], g.Results[j]
+			if a.Host != b.Host {
+				return a.Host < b.Host
+			}
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+			return a.Value < b.Value
+		})
+		golden = append(golden, g)
+	}
+	return golden
+}
+
+// corpus returns the sample bundle directories.
+func corpus(t *testing.T) []string {
+	var paths []string
+	for _, g := range corpusGlobs {
+		matches, err := filepath.Glob(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.IsDir() {
+				paths = append(paths, m)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// goldenPath returns the golden file of the sample bundle, e.g.
+// golden/checks-nodecount-ok.json for ../checks/nodecount/test_bundles/ok.
+func goldenPath(bundlePath string) string {
+	name := strings.TrimPrefix(filepath.ToSlash(bundlePath), "../")
+	name = strings.ReplaceAll(name, "/test_bundles/", "/")
+	if !strings.Contains(bundlePath, "..") {
+		name = "runner/" + name
+	}
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "/", "-"), "_", "-")
+	return filepath.Join("golden", name+".json")
+}
+
+// sampleBundle is a sample bundle and its golden file.
+type sampleBundle struct {
+	name   string
+	golden string
+	bundle bundle.Bundle
+}
+
+// sampleBundles opens the corpus bundles and builds the synthetic ones.
+func sampleBundles(t *testing.T) []sampleBundle {
+	var bundles []sampleBundle
+	for _, p := range corpus(t) {
+		b, err := bundle.New(p)
+		if err != nil {
+			// Not every test bundle is a valid bundle on purpose.
+			continue
+		}
+		bundles = append(bundles, sampleBundle{p, goldenPath(p), b})
+	}
+	names := make([]string, 0, len(samples))
+	for name := range samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		golden := filepath.Join("golden", "runner-sample-"+name+".json")
+		bundles = append(bundles, sampleBundle{"sample " + name, golden, samples[name](t)})
+	}
+	return bundles
+}
+
+func TestGolden(t *testing.T) {
+	covered := make(map[string]bool)
+	goldenFiles := make(map[string]bool)
+	for _, s := range sampleBundles(t) {
+		p, b := s.name, s.bundle
+		report, err := RunBundle(context.Background(), b, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range report.Checks {
+			if len(c.Results.OKs())+len(c.Results.Problems()) > 0 {
+				covered[c.Check.Name] = true
+			}
+		}
+		actual, err := json.MarshalIndent(normalize(report), "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual = append(actual, '\n')
+		path := s.golden
+		goldenFiles[filepath.Base(path)] = true
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(path, actual, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		expected, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Errorf("Cannot read the golden file of %v; run the test with -update to create it: %v", p, err)
+			continue
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("Results for %v differ from %v; if the change is intended, run the test with -update "+
+				"and review the diff.\n%v", p, path, diff(string(expected), string(actual)))
+		}
+	}
+	infos, err := ioutil.ReadDir("golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		if !goldenFiles[info.Name()] {
+			t.Errorf("Golden file golden/%v has no sample bundle; remove it", info.Name())
+		}
+	}
+	for _, c := range checks.Checks() {
+		if !covered[c.Name] {
+			t.Errorf("Check %v produced no OK or PROBLEM result on the sample bundles; "+
+				"add a sample bundle to its test_bundles, to runner/test_bundles or to runner/golden_samples_test.go", c.Name)
+		}
+	}
+}
+
+// diff returns the first lines which differ.
+func diff(expected string, actual string) string {
+	e := strings.Split(expected, "\n")
+	a := strings.Split(actual, "\n")
+	for i := 0; i < len(e) || i < len(a); i++ {
+		var el, al string
+		if i < len(e) {
+			el = e[i]
+		}
+		if i < len(a) {
+			al = a[i]
+		}
+		if el != al {
+			return fmt.Sprintf("line %v:\n- %v\n+ %v", i+1, el, al)
+		}
+	}
+	return ""
+}
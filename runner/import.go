@@ -1,4 +1,4 @@
-package cmd
+package runner
 
 import (
 	_ "github.com/mesosphere/bun/v2/checks/dcosnet/overlay"
@@ -8,6 +8,8 @@ import (
 	_ "github.com/mesosphere/bun/v2/checks/dcosrequirements/mem"
 	_ "github.com/mesosphere/bun/v2/checks/dcosversion"
 	_ "github.com/mesosphere/bun/v2/checks/health"
+	_ "github.com/mesosphere/bun/v2/checks/kubernetes/nodes"
+	_ "github.com/mesosphere/bun/v2/checks/kubernetes/pods"
 	_ "github.com/mesosphere/bun/v2/checks/marathon/deployments"
 	_ "github.com/mesosphere/bun/v2/checks/marathon/instances"
 	_ "github.com/mesosphere/bun/v2/checks/marathon/marathon_lb_1.14.1"
@@ -17,4 +19,15 @@ import (
 	_ "github.com/mesosphere/bun/v2/checks/mesos/offered_resources"
 	_ "github.com/mesosphere/bun/v2/checks/mesos/unregistered_agents"
 	_ "github.com/mesosphere/bun/v2/checks/nodecount"
+	_ "github.com/mesosphere/bun/v2/checks/security/plaintext_secrets"
+
+	"github.com/mesosphere/bun/v2/checks"
 )
+
+var searchChecksErr = checks.RegisterSearchChecks()
+
+// BuiltinChecksError returns an error if some of the built-in search checks
+// couldn't be registered. The rest of the checks are registered anyway.
+func BuiltinChecksError() error {
+	return searchChecksErr
+}
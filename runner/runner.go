@@ -0,0 +1,129 @@
+// Package runner runs the registered checks against a diagnostics bundle
+// and returns structured reports. It neither prints nor exits, so it can be
+// used to analyze bundles programmatically.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+// Options define which checks to run and how.
+type Options struct {
+	// Checks are names of the checks to run. All the registered checks
+	// which apply to the bundle flavor are run if it's empty.
+	Checks []string
+	// OnCheckDone is called after each check; it's optional.
+	OnCheckDone func(CheckReport)
+}
+
+// CheckReport is an outcome of a single check.
+type CheckReport struct {
+	Check    checks.Check
+	Results  checks.Results
+	Duration time.Duration
+}
+
+// Status returns the aggregated status of the check results.
+func (r CheckReport) Status() checks.Status {
+	return r.Results.Status()
+}
+
+// Report is an outcome of a bundle analysis.
+type Report struct {
+	Bundle   bundle.Bundle
+	Checks   []CheckReport
+	Duration time.Duration
+}
+
+// OK returns true if all the checks passed or were skipped.
+func (r Report) OK() bool {
+	for _, c := range r.Checks {
+		if s := c.Status(); s != checks.SOK && s != checks.SSkipped {
+			return false
+		}
+	}
+	return true
+}
+
+// Results returns results of all the checks in the order they were run.
+func (r Report) Results() []checks.Results {
+	results := make([]checks.Results, 0, len(r.Checks))
+	for _, c := range r.Checks {
+		results = append(results, c.Results)
+	}
+	return results
+}
+
+// Run opens the bundle located at the bundlePath and runs the checks against it.
+func Run(ctx context.Context, bundlePath string, opts Options) (Report, error) {
+	b, err := bundle.New(bundlePath)
+	if err != nil {
+		return Report{Bundle: b}, fmt.Errorf("cannot open a bundle: %v", err)
+	}
+	return RunBundle(ctx, b, opts)
+}
+
+// RunBundle runs the checks against the bundle. Checks are run in the
+// alphabetical order. A check which panics produces an UNDEFINED result
+// with the *checks.PanicError value. If the context is canceled, RunBundle returns the
+// report of the checks completed so far along with the context error.
+func RunBundle(ctx context.Context, b bundle.Bundle, opts Options) (Report, error) {
+	report := Report{Bundle: b}
+	selected, err := selectChecks(opts.Checks, b)
+	if err != nil {
+		return report, err
+	}
+	start := time.Now()
+	report.Checks = make([]CheckReport, 0, len(selected))
+	for _, c := range selected {
+		if err := ctx.Err(); err != nil {
+			report.Duration = time.Since(start)
+			return report, err
+		}
+		checkStart := time.Now()
+		r := CheckReport{Check: c, Results: c.SafeRun(b)}
+		r.Duration = time.Since(checkStart)
+		report.Checks = append(report.Checks, r)
+		if opts.OnCheckDone != nil {
+			opts.OnCheckDone(r)
+		}
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// selectChecks returns the checks with the names or, if names is empty, all
+// the checks which apply to the bundle flavor.
+func selectChecks(names []string, b bundle.Bundle) ([]checks.Check, error) {
+	var selected []checks.Check
+	if len(names) == 0 {
+		for _, c := range checks.Checks() {
+			if c.AppliesTo(b) {
+				selected = append(selected, c)
+			}
+		}
+	} else {
+		all := make(map[string]checks.Check)
+		for _, c := range checks.Checks() {
+			all[c.Name] = c
+		}
+		selected = make([]checks.Check, 0, len(names))
+		for _, name := range names {
+			c, ok := all[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown check %v", name)
+			}
+			selected = append(selected, c)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+	return selected, nil
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+func TestRunSelectedChecks(t *testing.T) {
+	var done []string
+	opts := Options{
+		Checks: []string{"node-count", "health"},
+		OnCheckDone: func(r CheckReport) {
+			done = append(done, r.Check.Name)
+		},
+	}
+	report, err := Run(context.Background(), "test_bundles/ok", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Checks) != 2 {
+		t.Fatalf("Expected 2 check reports, observed %v", len(report.Checks))
+	}
+	if report.Checks[0].Check.Name != "health" || report.Checks[1].Check.Name != "node-count" {
+		t.Errorf("Expected checks in the alphabetical order, observed %v", done)
+	}
+	if len(done) != 2 {
+		t.Errorf("Expected OnCheckDone to be called twice, observed %v", len(done))
+	}
+	if s := report.Checks[1].Status(); s != checks.SOK {
+		t.Errorf("Expected node-count Status = OK, observed %v", s)
+	}
+	if s := report.Checks[0].Status(); s != checks.SSkipped {
+		t.Errorf("Expected health Status = SKIPPED, observed %v", s)
+	}
+	if !report.OK() {
+		t.Error("Expected report with a skipped check to be OK")
+	}
+}
+
+func TestRunUnknownCheck(t *testing.T) {
+	_, err := Run(context.Background(), "test_bundles/ok", Options{Checks: []string{"no-such-check"}})
+	if err == nil {
+		t.Fatal("Expected error for an unknown check")
+	}
+}
+
+func TestRunNoBundle(t *testing.T) {
+	if _, err := Run(context.Background(), "test_bundles", Options{}); err == nil {
+		t.Fatal("Expected error when the bundle is not found")
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Run(ctx, "test_bundles/ok", Options{})
+	if err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, observed %v", err)
+	}
+	if len(report.Checks) != 0 {
+		t.Errorf("Expected no checks to run, observed %v", len(report.Checks))
+	}
+}
@@ -0,0 +1,204 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// KAWSSecretKey is an AWS secret access key.
+	KAWSSecretKey Kind = "AWS secret access key"
+	// KURLCredentials is a password in a URL, e.g. zk://user:password@host.
+	KURLCredentials = "URL credentials"
+	// KHighEntropy is a random-looking value, such as a token or a key.
+	KHighEntropy = "high-entropy value"
+)
+
+// Detector detects a secret of its Kind in a value of a field, a variable,
+// or a flag; name is empty if the value has no name.
+type Detector struct {
+	Kind   Kind
+	Detect func(name, value string) bool
+}
+
+var (
+	secretNameRegexp     = regexp.MustCompile(`^` + secretName + `$`)
+	awsKeyValueRegexp    = regexp.MustCompile(`^` + awsKeyRegexp.String() + `$`)
+	awsSecretRegexp      = regexp.MustCompile(`^[A-Za-z0-9/+]{40}$`)
+	jwtValueRegexp       = regexp.MustCompile(`^` + jwtRegexp.String() + `$`)
+	urlCredentialsRegexp = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:[^/\s@]+@`)
+	// tokenRegexp matches the values which might be tokens: long, without
+	// spaces, dots, or colons, so paths, URLs, and hostnames don't match.
+	tokenRegexp = regexp.MustCompile(`^[A-Za-z0-9+/_=-]{24,}$`)
+	// filePathRegexp matches the values of fields like ssh_private_key_path.
+	filePathRegexp = regexp.MustCompile(`^(\.{0,2}/|genconf/)\S*$`)
+	uuidRegexp     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// Detectors is the ruleset used by the Scan functions. The first matching
+// detector wins, so more specific detectors go first.
+var Detectors = []Detector{
+	{KPrivateKey, func(_, value string) bool {
+		return pemBeginRegexp.MatchString(value)
+	}},
+	{KJWT, func(_, value string) bool {
+		return jwtValueRegexp.MatchString(value)
+	}},
+	{KAWSAccessKey, func(_, value string) bool {
+		return awsKeyValueRegexp.MatchString(value)
+	}},
+	{KAWSSecretKey, func(name, value string) bool {
+		return strings.Contains(strings.ToLower(name), "aws") && awsSecretRegexp.MatchString(value)
+	}},
+	{KURLCredentials, func(_, value string) bool {
+		return urlCredentialsRegexp.MatchString(value)
+	}},
+	{KField, func(name, value string) bool {
+		return secretNameRegexp.MatchString(name) && value != "" &&
+			!notSecretRegexp.MatchString(strings.Trim(value, `"'`)) && !filePathRegexp.MatchString(value)
+	}},
+	{KHighEntropy, func(_, value string) bool {
+		return tokenRegexp.MatchString(value) && !uuidRegexp.MatchString(value) &&
+			entropy(value) >= 4.5
+	}},
+}
+
+// Detect returns the kind of the secret detected in the value by the first
+// matching detector.
+func Detect(name, value string) (Kind, bool) {
+	for _, d := range Detectors {
+		if d.Detect(name, value) {
+			return d.Kind, true
+		}
+	}
+	return "", false
+}
+
+// entropy returns the Shannon entropy of the string in bits per character.
+// Random base64 strings have about 5 bits; hex strings, e.g. hashes, have
+// 4 bits at most; words have less.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var e float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// ScanJSON finds the secrets in a JSON document. The Name of each finding is
+// the key path of the value, e.g. apps[0].env.DB_PASSWORD.
+func ScanJSON(data []byte) ([]Finding, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	walk("", "", v, &findings)
+	return findings, nil
+}
+
+// ScanYAML works like ScanJSON for YAML documents.
+func ScanYAML(data []byte) ([]Finding, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	walk("", "", v, &findings)
+	return findings, nil
+}
+
+// ScanEnviron finds the secrets in NAME=value pairs separated by the null
+// characters or new lines, e.g. in /proc/<pid>/environ. The Name of each
+// finding is the variable name.
+func ScanEnviron(data []byte) []Finding {
+	var findings []Finding
+	for _, pair := range strings.FieldsFunc(string(data), func(r rune) bool {
+		return r == 0 || r == '\n' || r == '\r'
+	}) {
+		name := pair
+		value := ""
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			name, value = pair[:i], pair[i+1:]
+		}
+		findings = append(findings, scanValue(name, name, value)...)
+	}
+	return findings
+}
+
+// walk finds the secrets in the decoded JSON or YAML value v at the key path.
+func walk(path string, name string, v interface{}, findings *[]Finding) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walk(join(path, k), k, v[k], findings)
+		}
+	case map[interface{}]interface{}:
+		keys := make([]string, 0, len(v))
+		values := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			key := fmt.Sprint(k)
+			keys = append(keys, key)
+			values[key] = value
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walk(join(path, k), k, values[k], findings)
+		}
+	case []interface{}:
+		for i, e := range v {
+			walk(fmt.Sprintf("%v[%v]", path, i), name, e, findings)
+		}
+	case string:
+		*findings = append(*findings, scanValue(path, name, v)...)
+	}
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// scanValue finds the secrets in the value itself or, if it's not a secret
+// as a whole, in its text, e.g. in the command line of a Marathon app.
+func scanValue(path string, name string, value string) []Finding {
+	if kind, ok := Detect(name, value); ok {
+		return []Finding{{Kind: kind, Name: path}}
+	}
+	var findings []Finding
+	var r Redactor
+	for _, line := range strings.SplitAfter(value, "\n") {
+		_, found := r.Redact(line)
+		for _, f := range found {
+			location := path
+			if f.Name != "" {
+				location += ": " + f.Name
+			}
+			findings = append(findings, Finding{Kind: f.Kind, Name: location})
+		}
+	}
+	return findings
+}
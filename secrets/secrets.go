@@ -0,0 +1,183 @@
+// Package secrets finds and masks secrets, such as passwords, tokens, and
+// private keys, in the lines of bundle files.
+package secrets
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mask replaces secret values.
+const Mask = "******"
+
+// Kind is a kind of a secret.
+type Kind string
+
+const (
+	// KField is a value of a field, a variable, or a flag which name suggests
+	// a secret, e.g. superuser_password_hash or AWS_SECRET_ACCESS_KEY.
+	KField Kind = "secret field"
+	// KPrivateKey is a PEM-encoded private key.
+	KPrivateKey = "private key"
+	// KJWT is a JSON Web Token, e.g. a DC/OS authentication token.
+	KJWT = "JSON Web Token"
+	// KBearer is a token in the HTTP Authorization header.
+	KBearer = "bearer token"
+	// KAWSAccessKey is an AWS access key ID.
+	KAWSAccessKey = "AWS access key"
+)
+
+// Finding is a secret found in a line.
+type Finding struct {
+	Kind Kind
+	// Name is the name of the field for the KField secrets found by
+	// Redactor, or the location of the secret found by the Scan functions.
+	Name string
+}
+
+// secretName matches names of fields, variables, and flags which hold secrets.
+const secretName = `[A-Za-z0-9_.-]*(?i:password|passwd|secret|token|private[_-]?key|access[_-]?key|api[_-]?key|credential)[A-Za-z0-9_.-]*`
+
+var (
+	// "name": "value"
+	jsonFieldRegexp = regexp.MustCompile(`("(` + secretName + `)"\s*:\s*)"((?:[^"\\]|\\.)*)"`)
+	// \"name\": \"value\" in JSON embedded in a JSON string, e.g. a docker config.
+	escapedJSONFieldRegexp = regexp.MustCompile(`(\\"(` + secretName + `)\\"\s*:\s*)\\"([^"\\]*)\\"`)
+	// name=value, --name=value
+	assignmentRegexp = regexp.MustCompile(`(^|[\s\x00,;'"])(-{0,2}(` + secretName + `)=)([^\s\x00,;'"]+)`)
+	// name: value
+	yamlFieldRegexp = regexp.MustCompile(`^(\s*-?\s*(` + secretName + `)\s*:\s+)(\S.*?)\s*$`)
+	bearerRegexp    = regexp.MustCompile(`(?i)(bearer\s+)([A-Za-z0-9._~+/-]+=*)`)
+	jwtRegexp       = regexp.MustCompile(`eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]+`)
+	awsKeyRegexp    = regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)
+	pemBeginRegexp  = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	pemEndRegexp    = regexp.MustCompile(`-----END [A-Z ]*PRIVATE KEY-----`)
+	// notSecretRegexp matches values which cannot be secrets, e.g. token_ttl: 30.
+	notSecretRegexp = regexp.MustCompile(`^(?i:true|false|null|none|[0-9.]+[a-z]?|\*+)$`)
+)
+
+// Redactor masks secrets in consecutive lines of a file. It's not safe for
+// concurrent use; create a new Redactor for each file.
+type Redactor struct {
+	inPrivateKey bool
+}
+
+// Redact returns the line with the secrets masked and the list of the found
+// secrets. Multi-line private keys are masked as a whole.
+func (r *Redactor) Redact(line string) (string, []Finding) {
+	var findings []Finding
+	if r.inPrivateKey {
+		loc := pemEndRegexp.FindStringIndex(line)
+		if loc == nil {
+			return maskLine(line), nil
+		}
+		r.inPrivateKey = false
+		line = Mask + line[loc[0]:]
+	}
+	line = r.redactPrivateKeys(line, &findings)
+	line = replace(jsonFieldRegexp, line, func(groups []string) string {
+		if notSecretRegexp.MatchString(groups[3]) || groups[3] == "" {
+			return groups[0]
+		}
+		findings = append(findings, Finding{Kind: KField, Name: groups[2]})
+		return groups[1] + `"` + Mask + `"`
+	})
+	line = replace(escapedJSONFieldRegexp, line, func(groups []string) string {
+		if notSecretRegexp.MatchString(groups[3]) || groups[3] == "" {
+			return groups[0]
+		}
+		findings = append(findings, Finding{Kind: KField, Name: groups[2]})
+		return groups[1] + `\"` + Mask + `\"`
+	})
+	line = replace(assignmentRegexp, line, func(groups []string) string {
+		if notSecretRegexp.MatchString(groups[4]) {
+			return groups[0]
+		}
+		findings = append(findings, Finding{Kind: KField, Name: groups[3]})
+		return groups[1] + groups[2] + Mask
+	})
+	line = replace(yamlFieldRegexp, line, func(groups []string) string {
+		value := strings.Trim(groups[3], `"'`)
+		if notSecretRegexp.MatchString(value) || value == "" {
+			return groups[0]
+		}
+		findings = append(findings, Finding{Kind: KField, Name: groups[2]})
+		return groups[1] + Mask + line[len(strings.TrimRight(line, " \t\r\n")):]
+	})
+	line = replace(bearerRegexp, line, func(groups []string) string {
+		if groups[2] == Mask {
+			return groups[0]
+		}
+		findings = append(findings, Finding{Kind: KBearer})
+		return groups[1] + Mask
+	})
+	line = replace(jwtRegexp, line, func([]string) string {
+		findings = append(findings, Finding{Kind: KJWT})
+		return Mask
+	})
+	line = replace(awsKeyRegexp, line, func([]string) string {
+		findings = append(findings, Finding{Kind: KAWSAccessKey})
+		return Mask
+	})
+	return line, findings
+}
+
+// redactPrivateKeys masks the private keys which start in the line. If a key
+// doesn't end in the line, the rest of the line and the next lines up to the
+// end of the key are masked.
+func (r *Redactor) redactPrivateKeys(line string, findings *[]Finding) string {
+	var b strings.Builder
+	for {
+		begin := pemBeginRegexp.FindStringIndex(line)
+		if begin == nil {
+			b.WriteString(line)
+			return b.String()
+		}
+		*findings = append(*findings, Finding{Kind: KPrivateKey})
+		b.WriteString(line[:begin[1]])
+		rest := line[begin[1]:]
+		end := pemEndRegexp.FindStringIndex(rest)
+		if end == nil {
+			r.inPrivateKey = true
+			b.WriteString(maskLine(rest))
+			return b.String()
+		}
+		b.WriteString(Mask)
+		line = rest[end[0]:]
+		b.WriteString(line[:end[1]-end[0]])
+		line = line[end[1]-end[0]:]
+	}
+}
+
+// maskLine masks the line keeping its line ending.
+func maskLine(line string) string {
+	content := strings.TrimRight(line, "\r\n")
+	if content == "" {
+		return line
+	}
+	return Mask + line[len(content):]
+}
+
+// replace works like regexp.ReplaceAllStringFunc but passes the submatches
+// to the function.
+func replace(re *regexp.Regexp, s string, f func(groups []string) string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		groups := make([]string, len(m)/2)
+		for i := range groups {
+			if m[2*i] >= 0 {
+				groups[i] = s[m[2*i]:m[2*i+1]]
+			}
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(f(groups))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+	"github.com/mesosphere/bun/v2/runner"
+)
+
+// checkInfo describes a registered check.
+type checkInfo struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Cure        string      `json:"cure"`
+	Tags        []string    `json:"tags,omitempty"`
+	Doc         *checks.Doc `json:"doc,omitempty"`
+}
+
+// checkReport is the JSON representation of runner.CheckReport.
+type checkReport struct {
+	checkInfo
+	Status   checks.Status `json:"status"`
+	Summary  string        `json:"summary"`
+	Partial  bool          `json:"partial"`
+	Duration float64       `json:"durationSeconds"`
+	Results  []result      `json:"results"`
+}
+
+// result is the JSON representation of checks.Result.
+type result struct {
+	Status   checks.Status         `json:"status"`
+	Host     *host                 `json:"host,omitempty"`
+	Value    string                `json:"value,omitempty"`
+	Partial  bool                  `json:"partial,omitempty"`
+	Evidence []bundle.FileTypeName `json:"evidence,omitempty"`
+}
+
+type host struct {
+	ID       string         `json:"id"`
+	Type     bundle.DirType `json:"type"`
+	IP       bundle.IP      `json:"ip"`
+	Hostname string         `json:"hostname,omitempty"`
+}
+
+func info(c checks.Check) checkInfo {
+	i := checkInfo{Name: c.Name, Description: c.Description, Cure: c.Cure, Tags: c.Tags}
+	if !c.Doc.IsEmpty() {
+		i.Doc = &c.Doc
+	}
+	return i
+}
+
+func (s *Server) handleChecks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	registered := checks.Checks()
+	list := make([]checkInfo, 0, len(registered))
+	for _, c := range registered {
+		list = append(list, info(c))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func checkResults(report runner.Report) []checkReport {
+	reports := make([]checkReport, 0, len(report.Checks))
+	for _, c := range report.Checks {
+		cr := checkReport{
+			checkInfo: info(c.Check),
+			Status:    c.Status(),
+			Partial:   c.Results.Partial(),
+			Duration:  c.Duration.Seconds(),
+			Results:   make([]result, 0, len(c.Results)),
+		}
+		switch cr.Status {
+		case checks.SOK:
+			cr.Summary = c.Check.OKSummary
+		case checks.SProblem:
+			cr.Summary = c.Check.ProblemSummary
+		case checks.SSkipped:
+			cr.Summary = "The bundle doesn't contain the files the check needs."
+		default:
+			cr.Summary = "Couldn't perform the check."
+		}
+		for _, r := range c.Results {
+			res := result{Status: r.Status, Partial: r.Partial, Evidence: r.Evidence}
+			if r.Value != nil {
+				res.Value = fmt.Sprint(r.Value)
+			}
+			if r.IsHostSet() {
+				h := toHost(r.Host)
+				res.Host = &h
+			}
+			cr.Results = append(cr.Results, res)
+		}
+		reports = append(reports, cr)
+	}
+	return reports
+}
+
+func hosts(b bundle.Bundle) []host {
+	list := make([]host, 0, len(b.Hosts))
+	for _, h := range b.Hosts {
+		list = append(list, toHost(h))
+	}
+	return list
+}
+
+func toHost(h bundle.Host) host {
+	return host{ID: hostID(h), Type: h.Type, IP: h.IP, Hostname: h.IP.Hostname}
+}
+
+// hostID returns the value of the host query parameter which selects the host.
+func hostID(h bundle.Host) string {
+	if h.IP.IsZero() {
+		return string(h.Type)
+	}
+	return h.IP.String()
+}
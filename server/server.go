@@ -0,0 +1,477 @@
+// Package server implements an HTTP service which accepts diagnostics
+// bundles, runs the registered checks against them asynchronously, and
+// exposes the results, the hosts, and the bundle files via a REST API and
+// a minimal web UI.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/runner"
+	"github.com/mesosphere/bun/v2/tools/files"
+)
+
+// Status is the processing status of a bundle.
+type Status string
+
+const (
+	// SPending means that the bundle waits for a free worker.
+	SPending Status = "pending"
+	// SRunning means that the checks are running.
+	SRunning = "running"
+	// SDone means that the results are ready.
+	SDone = "done"
+	// SFailed means that the bundle couldn't be opened or checked.
+	SFailed = "failed"
+)
+
+// Options configure the server.
+type Options struct {
+	// DataDir is where the uploaded bundles are extracted.
+	DataDir string
+	// AllowLocalPaths allows to submit paths on the server filesystem.
+	AllowLocalPaths bool
+	// Workers is the number of bundles checked concurrently; 1 if not set.
+	Workers int
+	// MaxUploadSize limits the size of the uploaded archives in bytes;
+	// it's unlimited if not set.
+	MaxUploadSize int64
+	// MaxExtractedSize limits the total size of the files extracted from an
+	// uploaded archive in bytes; it's unlimited if not set. It protects
+	// the DataDir from archive bombs.
+	MaxExtractedSize int64
+}
+
+// Server is an http.Handler serving the REST API and the web UI.
+type Server struct {
+	opts    Options
+	mux     *http.ServeMux
+	workers chan struct{}
+
+	mu      sync.RWMutex
+	bundles map[string]*entry
+}
+
+// entry is a submitted bundle.
+type entry struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Status  Status    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	// dir is the directory of the extracted upload; it's empty for local paths.
+	dir    string
+	bundle bundle.Bundle
+	report runner.Report
+}
+
+//go:embed static
+var static embed.FS
+
+// New creates a Server.
+func New(opts Options) (*Server, error) {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.DataDir == "" {
+		return nil, errors.New("data directory is not specified")
+	}
+	if err := os.MkdirAll(opts.DataDir, 0700); err != nil {
+		return nil, err
+	}
+	s := &Server{
+		opts:    opts,
+		mux:     http.NewServeMux(),
+		workers: make(chan struct{}, opts.Workers),
+		bundles: make(map[string]*entry),
+	}
+	root, err := fs.Sub(static, "static")
+	if err != nil {
+		return nil, err
+	}
+	s.mux.Handle("/", http.FileServer(http.FS(root)))
+	s.mux.HandleFunc("/api/bundles", s.handleBundles)
+	s.mux.HandleFunc("/api/bundles/", s.handleBundle)
+	s.mux.HandleFunc("/api/checks", s.handleChecks)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Submit adds the bundle located at the path on the server filesystem and
+// starts checking it. It's used for the bundles given on the command line
+// and doesn't depend on Options.AllowLocalPaths.
+func (s *Server) Submit(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%v is not a directory", path)
+	}
+	e, err := s.add(filepath.Base(path), "")
+	if err != nil {
+		return "", err
+	}
+	go s.check(e, path)
+	return e.ID, nil
+}
+
+func (s *Server) add(name string, dir string) (*entry, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	e := &entry{
+		ID:      hex.EncodeToString(id),
+		Name:    name,
+		Status:  SPending,
+		Created: time.Now().UTC(),
+		dir:     dir,
+	}
+	s.mu.Lock()
+	s.bundles[e.ID] = e
+	s.mu.Unlock()
+	return e, nil
+}
+
+// check opens the bundle at the path, or extracts the archive first if the
+// entry has an upload directory, and runs the checks.
+func (s *Server) check(e *entry, path string) {
+	s.workers <- struct{}{}
+	defer func() { <-s.workers }()
+	s.setStatus(e, SRunning, nil)
+	var b bundle.Bundle
+	var err error
+	if e.dir != "" {
+		extracted := filepath.Join(e.dir, "bundle")
+		err = bundle.ExtractLimited(path, extracted, s.opts.MaxExtractedSize)
+		_ = os.Remove(path)
+		if err == nil {
+			b, err = bundle.OpenExtracted(extracted)
+		} else {
+			_ = os.RemoveAll(extracted)
+		}
+	} else {
+		b, err = bundle.New(path)
+	}
+	if err != nil {
+		s.setStatus(e, SFailed, err)
+		return
+	}
+	report, err := runner.RunBundle(context.Background(), b, runner.Options{})
+	s.mu.Lock()
+	e.bundle = b
+	e.report = report
+	s.mu.Unlock()
+	if err != nil {
+		s.setStatus(e, SFailed, err)
+		return
+	}
+	s.setStatus(e, SDone, nil)
+}
+
+func (s *Server) setStatus(e *entry, status Status, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.Status = status
+	if err != nil {
+		e.Error = err.Error()
+		log.Printf("bun serve: bundle %v (%v): %v", e.ID, e.Name, err)
+	}
+}
+
+// get returns a copy of the entry.
+func (s *Server) get(id string) (entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.bundles[id]
+	if !ok {
+		return entry{}, false
+	}
+	return *e, true
+}
+
+// handleBundles lists the bundles and accepts new ones: a multipart form
+// with the archive in the "file" field, or a JSON {"path": "..."} object.
+func (s *Server) handleBundles(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.RLock()
+		list := make([]entry, 0, len(s.bundles))
+		for _, e := range s.bundles {
+			list = append(list, *e)
+		}
+		s.mu.RUnlock()
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Created.After(list[j].Created)
+		})
+		writeJSON(w, http.StatusOK, list)
+	case http.MethodPost:
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			s.submitPath(w, r)
+			return
+		}
+		s.upload(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+func (s *Server) submitPath(w http.ResponseWriter, r *http.Request) {
+	if !s.opts.AllowLocalPaths {
+		writeError(w, http.StatusForbidden, errors.New("submitting local paths is not allowed"))
+		return
+	}
+	var req struct {
+		Path string `json:"path"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Path == "" {
+		writeError(w, http.StatusBadRequest, errors.New(`expected {"path": "<bundle directory>"}`))
+		return
+	}
+	id, err := s.Submit(req.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	e, _ := s.get(id)
+	writeJSON(w, http.StatusAccepted, e)
+}
+
+func (s *Server) upload(w http.ResponseWriter, r *http.Request) {
+	if s.opts.MaxUploadSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxUploadSize)
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("expected a multipart form with the archive in the file field: %w", err))
+		return
+	}
+	defer file.Close()
+	dir, err := ioutil.TempDir(s.opts.DataDir, "bundle-")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	archive := filepath.Join(dir, "upload")
+	if err := saveUpload(archive, file); err != nil {
+		_ = os.RemoveAll(dir)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	e, err := s.add(filepath.Base(header.Filename), dir)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	go s.check(e, archive)
+	created, _ := s.get(e.ID)
+	writeJSON(w, http.StatusAccepted, created)
+}
+
+// handleBundle serves the /api/bundles/{id}[/{resource}[/{name}]] endpoints.
+func (s *Server) handleBundle(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/bundles/"), "/"), "/")
+	e, ok := s.get(parts[0])
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("bundle %v not found", parts[0]))
+		return
+	}
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, e)
+		case http.MethodDelete:
+			s.delete(w, e)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		}
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	if e.Status != SDone {
+		writeError(w, http.StatusConflict, fmt.Errorf("bundle %v is %v", e.ID, e.Status))
+		return
+	}
+	switch {
+	case len(parts) == 2 && parts[1] == "checks":
+		writeJSON(w, http.StatusOK, checkResults(e.report))
+	case len(parts) == 2 && parts[1] == "hosts":
+		writeJSON(w, http.StatusOK, hosts(e.bundle))
+	case len(parts) == 2 && parts[1] == "files":
+		s.listFiles(w, r, e)
+	case len(parts) == 3 && parts[1] == "files":
+		s.catFile(w, r, e, bundle.FileTypeName(parts[2]))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("%v not found", r.URL.Path))
+	}
+}
+
+func (s *Server) delete(w http.ResponseWriter, e entry) {
+	if e.Status == SPending || e.Status == SRunning {
+		writeError(w, http.StatusConflict, fmt.Errorf("bundle %v is %v", e.ID, e.Status))
+		return
+	}
+	s.mu.Lock()
+	delete(s.bundles, e.ID)
+	s.mu.Unlock()
+	if e.dir != "" {
+		if err := os.RemoveAll(e.dir); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// selector returns the host selector of the "host" query parameters.
+func selector(r *http.Request) files.Selector {
+	return files.Selector{Hosts: r.URL.Query()["host"], Leader: r.URL.Query().Get("leader") == "true"}
+}
+
+func (s *Server) listFiles(w http.ResponseWriter, r *http.Request, e entry) {
+	entries, err := files.List(e.bundle, selector(r))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	type fileType struct {
+		Name        bundle.FileTypeName `json:"name"`
+		ContentType bundle.ContentType  `json:"contentType"`
+		Description string              `json:"description,omitempty"`
+		Hosts       []string            `json:"hosts"`
+	}
+	list := make([]fileType, 0, len(entries))
+	for _, entry := range entries {
+		t := fileType{
+			Name:        entry.FileType.Name,
+			ContentType: entry.FileType.ContentType,
+			Description: entry.FileType.Description,
+		}
+		for _, h := range entry.Hosts {
+			t.Hosts = append(t.Hosts, hostID(h))
+		}
+		list = append(list, t)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// catFile writes the decompressed file of the given type. If it's found on
+// several of the selected hosts, each file is preceded by a header.
+func (s *Server) catFile(w http.ResponseWriter, r *http.Request, e entry, name bundle.FileTypeName) {
+	t, err := bundle.GetFileType(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	lw := &lazyWriter{w: w, contentType: "text/plain; charset=utf-8"}
+	if t.ContentType == bundle.CTJson {
+		lw.contentType = "application/json"
+	}
+	pretty := r.URL.Query().Get("pretty") == "true"
+	err = files.Cat(e.bundle, name, selector(r), pretty, lw)
+	if errors.Is(err, bundle.ErrPartialFile) {
+		err = nil
+	}
+	switch {
+	case err != nil && !lw.written && errors.Is(err, bundle.ErrFileNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil && !lw.written:
+		writeError(w, http.StatusBadRequest, err)
+	case err != nil:
+		log.Printf("bun serve: cannot write %v of the bundle %v: %v", name, e.ID, err)
+	case !lw.written:
+		lw.writeHeader()
+	}
+}
+
+// lazyWriter sets the Content-Type header on the first write, so an error
+// can still be returned as JSON if nothing has been written.
+type lazyWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	written     bool
+}
+
+func (l *lazyWriter) writeHeader() {
+	l.w.Header().Set("Content-Type", l.contentType)
+	l.w.WriteHeader(http.StatusOK)
+	l.written = true
+}
+
+func (l *lazyWriter) Write(p []byte) (int, error) {
+	if !l.written {
+		l.writeHeader()
+	}
+	return l.w.Write(p)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Printf("bun serve: cannot write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+// Serve listens on the addr address until the context is canceled.
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	srv := &http.Server{Addr: addr, Handler: s}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func saveUpload(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
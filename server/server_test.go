@@ -0,0 +1,226 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// archive creates a zip or a tar archive of the directory; the entries are
+// prefixed with the name of the directory.
+func archive(t *testing.T, dir string, format string) []byte {
+	var buf bytes.Buffer
+	var add func(name string, data []byte) error
+	var closer io.Closer
+	switch format {
+	case "zip":
+		z := zip.NewWriter(&buf)
+		add = func(name string, data []byte) error {
+			w, err := z.Create(name)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		}
+		closer = z
+	case "tar":
+		tw := tar.NewWriter(&buf)
+		add = func(name string, data []byte) error {
+			if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+				return err
+			}
+			_, err := tw.Write(data)
+			return err
+		}
+		closer = tw
+	}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(filepath.Dir(dir), path)
+		if err != nil {
+			return err
+		}
+		return add(filepath.ToSlash(rel), data)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func upload(t *testing.T, url string, data []byte) (int, map[string]interface{}) {
+	var body bytes.Buffer
+	m := multipart.NewWriter(&body)
+	w, err := m.CreateFormFile("file", "bundle.archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url+"/api/bundles", m.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var v map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, v
+}
+
+func get(t *testing.T, url string, v interface{}) int {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if s, ok := v.(*string); ok {
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*s = string(data)
+		return resp.StatusCode
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func wait(t *testing.T, url string, id string) map[string]interface{} {
+	var e map[string]interface{}
+	for i := 0; i < 100; i++ {
+		get(t, url+"/api/bundles/"+id, &e)
+		if e["status"] == "done" || e["status"] == "failed" {
+			return e
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("Bundle %v wasn't checked in time: %v", id, e)
+	return nil
+}
+
+func TestUpload(t *testing.T) {
+	for _, format := range []string{"zip", "tar"} {
+		t.Run(format, func(t *testing.T) {
+			s, err := New(Options{DataDir: t.TempDir()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ts := httptest.NewServer(s)
+			defer ts.Close()
+			status, e := upload(t, ts.URL, archive(t, "test_bundles/ok", format))
+			if status != http.StatusAccepted {
+				t.Fatalf("Expected 202, observed %v: %v", status, e)
+			}
+			id := e["id"].(string)
+			if e := wait(t, ts.URL, id); e["status"] != "done" {
+				t.Fatalf("Expected the bundle to be checked, observed %v", e)
+			}
+			var reports []checkReport
+			get(t, ts.URL+"/api/bundles/"+id+"/checks", &reports)
+			found := false
+			for _, r := range reports {
+				if r.Name == "dcos-version" {
+					found = true
+					if r.Status != "OK" || len(r.Results) != 1 || r.Results[0].Value != "2.1.0" {
+						t.Errorf("Expected dcos-version 2.1.0, observed %+v", r)
+					}
+				}
+			}
+			if !found {
+				t.Error("Expected dcos-version check results")
+			}
+			var hosts []host
+			get(t, ts.URL+"/api/bundles/"+id+"/hosts", &hosts)
+			if len(hosts) != 1 || hosts[0].ID != "10.0.0.1" || hosts[0].Type != "master" {
+				t.Errorf("Expected master 10.0.0.1, observed %+v", hosts)
+			}
+			var state string
+			status = get(t, ts.URL+"/api/bundles/"+id+"/files/mesos-master-state?host=10.0.0.1", &state)
+			if status != http.StatusOK || !strings.Contains(state, `"hostname": "10.0.0.1"`) {
+				t.Errorf("Expected decompressed mesos-master-state, observed %v %q", status, state)
+			}
+			var notFound map[string]string
+			status = get(t, ts.URL+"/api/bundles/"+id+"/files/mesos-agent-state", &notFound)
+			if status != http.StatusNotFound || notFound["error"] == "" {
+				t.Errorf("Expected 404 with an error, observed %v %v", status, notFound)
+			}
+		})
+	}
+}
+
+func TestUploadInvalidArchive(t *testing.T) {
+	s, err := New(Options{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	_, e := upload(t, ts.URL, []byte("not an archive"))
+	if e := wait(t, ts.URL, e["id"].(string)); e["status"] != "failed" || e["error"] == "" {
+		t.Errorf("Expected the bundle to fail, observed %v", e)
+	}
+}
+
+func TestUploadTooLarge(t *testing.T) {
+	dataDir := t.TempDir()
+	s, err := New(Options{DataDir: dataDir, MaxExtractedSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	_, e := upload(t, ts.URL, archive(t, "test_bundles/ok", "zip"))
+	e = wait(t, ts.URL, e["id"].(string))
+	if e["status"] != "failed" || !strings.Contains(e["error"].(string), "too large") {
+		t.Errorf("Expected the bundle to fail as too large, observed %v", e)
+	}
+	if extracted, _ := filepath.Glob(filepath.Join(dataDir, "*", "*")); len(extracted) != 0 {
+		t.Errorf("Expected the extracted files to be removed, observed %v", extracted)
+	}
+}
+
+func TestSubmitPathNotAllowed(t *testing.T) {
+	s, err := New(Options{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	resp, err := http.Post(ts.URL+"/api/bundles", "application/json", strings.NewReader(`{"path": "/"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403, observed %v", resp.StatusCode)
+	}
+}
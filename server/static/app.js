@@ -0,0 +1,113 @@
+"use strict";
+
+function el(tag, text, className) {
+  const e = document.createElement(tag);
+  if (text !== undefined) e.textContent = text;
+  if (className) e.className = className;
+  return e;
+}
+
+function docElements(doc) {
+  const elements = [];
+  if (doc.remediation) {
+    const steps = el("ol");
+    for (const step of doc.remediation) steps.append(el("li", step, "step"));
+    elements.push(el("p", "Remediation:"), steps);
+  }
+  if (doc.links) {
+    const links = el("ul");
+    for (const l of doc.links) {
+      const a = el("a", l.title || l.url);
+      a.href = l.url;
+      a.target = "_blank";
+      const item = el("li");
+      item.append(a);
+      links.append(item);
+    }
+    elements.push(el("p", "Links:"), links);
+  }
+  return elements;
+}
+
+async function getJSON(url) {
+  const resp = await fetch(url);
+  const body = await resp.json();
+  if (!resp.ok) throw new Error(body.error);
+  return body;
+}
+
+async function loadBundles() {
+  const tbody = document.querySelector("#bundles tbody");
+  const bundles = await getJSON("api/bundles");
+  tbody.replaceChildren();
+  for (const b of bundles) {
+    const row = el("tr");
+    const name = el("a", b.name);
+    name.onclick = () => showBundle(b.id);
+    row.append(el("td"), el("td", b.status + (b.error ? ": " + b.error : "")),
+      el("td", new Date(b.created).toLocaleString()));
+    row.firstChild.append(name);
+    tbody.append(row);
+  }
+  if (bundles.some(b => b.status === "pending" || b.status === "running")) {
+    setTimeout(loadBundles, 2000);
+  }
+}
+
+async function showBundle(id) {
+  const container = document.getElementById("checks");
+  container.replaceChildren();
+  const b = await getJSON("api/bundles/" + id);
+  document.getElementById("title").textContent = b.name;
+  if (b.status !== "done") {
+    container.append(el("p", "The bundle is " + b.status + (b.error ? ": " + b.error : ".")));
+    if (b.status === "pending" || b.status === "running") setTimeout(() => showBundle(id), 2000);
+    return;
+  }
+  const order = { PROBLEM: 0, UNDEFINED: 1, OK: 2, SKIPPED: 3 };
+  const reports = await getJSON("api/bundles/" + id + "/checks");
+  reports.sort((a, b) => order[a.status] - order[b.status] || a.name.localeCompare(b.name));
+  for (const c of reports) {
+    const details = el("details");
+    details.open = c.status === "PROBLEM";
+    const summary = el("summary");
+    summary.append(el("span", "[" + c.status + "] ", c.status), c.name + ": " + c.summary +
+      (c.partial ? " (partial data)" : ""));
+    details.append(summary, el("p", c.description));
+    if (c.status === "PROBLEM") {
+      details.append(el("p", "Cure: " + c.cure));
+      if (c.doc) details.append(...docElements(c.doc));
+    }
+    const table = el("table");
+    for (const r of c.results) {
+      const row = el("tr");
+      const hostCell = el("td", r.host ? r.host.type + " " + r.host.id : "");
+      for (const fileType of r.evidence || []) {
+        const link = el("a", fileType);
+        const params = r.host ? "?host=" + encodeURIComponent(r.host.id) : "";
+        link.href = "api/bundles/" + id + "/files/" + encodeURIComponent(fileType) + params;
+        link.target = "_blank";
+        hostCell.append(el("br"), link);
+      }
+      row.append(el("td", r.status, r.status), hostCell, el("td", r.value || "", "value"));
+      table.append(row);
+    }
+    details.append(table);
+    container.append(details);
+  }
+}
+
+document.getElementById("upload").onsubmit = async (event) => {
+  event.preventDefault();
+  const resp = await fetch("api/bundles", { method: "POST", body: new FormData(event.target) });
+  const body = await resp.json();
+  if (!resp.ok) {
+    alert(body.error);
+    return;
+  }
+  event.target.reset();
+  await loadBundles();
+  showBundle(body.id);
+};
+
+loadBundles();
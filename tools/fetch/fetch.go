@@ -0,0 +1,334 @@
+// Package fetch creates a diagnostics bundle on a DC/OS cluster and downloads
+// it via the dcos-diagnostics bundle API.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// bundlesPath is the path of the cluster bundles endpoint of dcos-diagnostics.
+const bundlesPath = "/system/health/v1/diagnostics/"
+
+// Status is the status of a bundle reported by dcos-diagnostics.
+type Status string
+
+// Bundle statuses.
+const (
+	SUnknown    Status = "Unknown"
+	SStarted    Status = "Started"
+	SInProgress Status = "InProgress"
+	SDone       Status = "Done"
+	SCanceled   Status = "Canceled"
+	SDeleted    Status = "Deleted"
+	SFailed     Status = "Failed"
+)
+
+// ErrBundleFailed is returned if the cluster could not create the bundle.
+var ErrBundleFailed = errors.New("bundle creation did not succeed")
+
+// BundleInfo describes a bundle on the cluster.
+type BundleInfo struct {
+	ID     string   `json:"id"`
+	Status Status   `json:"status"`
+	Size   int64    `json:"size"`
+	Errors []string `json:"errors"`
+}
+
+// Options configures the Fetcher.
+type Options struct {
+	// URL is the URL of the cluster, e.g. https://dcos.example.com.
+	URL string
+	// Token is the DC/OS authentication token; it's not sent if empty.
+	Token string
+	// Client is the HTTP client; http.DefaultClient is used if nil.
+	Client *http.Client
+	// PollInterval is the interval of the bundle status requests.
+	PollInterval time.Duration
+	// Progress receives the progress messages; they are discarded if nil.
+	Progress io.Writer
+}
+
+// Fetcher creates and downloads bundles.
+type Fetcher struct {
+	opts Options
+	base *url.URL
+}
+
+// New returns a Fetcher for the cluster.
+func New(opts Options) (*Fetcher, error) {
+	base, err := url.Parse(strings.TrimSuffix(opts.URL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster URL %v: %w", opts.URL, err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("invalid cluster URL %v: expected an http or https URL", opts.URL)
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	if opts.Progress == nil {
+		opts.Progress = ioutil.Discard
+	}
+	return &Fetcher{opts: opts, base: base}, nil
+}
+
+// Fetch downloads the bundle with the id to the dir directory and returns the
+// path of the archive. If id is empty, a new bundle is created first. Fetch
+// waits until the cluster finishes the bundle, and resumes the download if a
+// previous one was interrupted.
+func (f *Fetcher) Fetch(ctx context.Context, id string, dir string) (string, error) {
+	if id == "" {
+		var err error
+		if id, err = f.Create(ctx); err != nil {
+			return "", err
+		}
+	}
+	archive := filepath.Join(dir, "bundle-"+id+".zip")
+	if _, err := os.Stat(archive); err == nil {
+		fmt.Fprintf(f.opts.Progress, "Bundle %v is already downloaded to %v\n", id, archive)
+		return archive, nil
+	}
+	info, err := f.Wait(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if err := f.Download(ctx, id, info.Size, archive); err != nil {
+		return "", err
+	}
+	return archive, nil
+}
+
+// Create starts the creation of a bundle with the data from all the masters
+// and agents and returns the bundle ID.
+func (f *Fetcher) Create(ctx context.Context) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(map[string]bool{"masters": true, "agents": true})
+	if err != nil {
+		return "", err
+	}
+	resp, err := f.do(ctx, http.MethodPut, id, bytes.NewReader(body), nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot create a bundle: %w", err)
+	}
+	_ = resp.Body.Close()
+	fmt.Fprintf(f.opts.Progress, "Creating bundle %v\n", id)
+	return id, nil
+}
+
+// Wait polls the status of the bundle until the cluster finishes it.
+func (f *Fetcher) Wait(ctx context.Context, id string) (BundleInfo, error) {
+	var last Status
+	for {
+		info, err := f.Info(ctx, id)
+		if err != nil {
+			return info, err
+		}
+		if info.Status != last {
+			fmt.Fprintf(f.opts.Progress, "Bundle %v: %v\n", id, info.Status)
+			last = info.Status
+		}
+		switch info.Status {
+		case SDone:
+			return info, nil
+		case SCanceled, SDeleted, SFailed:
+			if len(info.Errors) > 0 {
+				return info, fmt.Errorf("%w: bundle %v is %v: %v",
+					ErrBundleFailed, id, info.Status, strings.Join(info.Errors, "; "))
+			}
+			return info, fmt.Errorf("%w: bundle %v is %v", ErrBundleFailed, id, info.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return info, ctx.Err()
+		case <-time.After(f.opts.PollInterval):
+		}
+	}
+}
+
+// Info returns the status of the bundle.
+func (f *Fetcher) Info(ctx context.Context, id string) (BundleInfo, error) {
+	var info BundleInfo
+	resp, err := f.do(ctx, http.MethodGet, id, nil, nil)
+	if err != nil {
+		return info, fmt.Errorf("cannot get the status of bundle %v: %w", id, err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return info, fmt.Errorf("cannot parse the status of bundle %v: %w", id, err)
+	}
+	return info, nil
+}
+
+// Download downloads the bundle to the path. The data is written to the
+// path with the .part suffix first; if the file exists, the download resumes
+// from its end. The size is the expected size of the bundle, or 0 if unknown.
+func (f *Fetcher) Download(ctx context.Context, id string, size int64, path string) error {
+	part := path + ".part"
+	file, err := os.OpenFile(part, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := f.do(ctx, http.MethodGet, id+"/file", nil, header)
+	if err != nil {
+		var status statusError
+		if errors.As(err, &status) && status.code == http.StatusRequestedRangeNotSatisfiable &&
+			(size == 0 || offset == size) {
+			// The previous download was interrupted after the last byte.
+			return finish(file, part, path)
+		}
+		return fmt.Errorf("cannot download bundle %v: %w", id, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusPartialContent {
+		fmt.Fprintf(f.opts.Progress, "Resuming the download of bundle %v from %v\n", id, formatSize(offset))
+	} else {
+		// The server ignored the Range header and sends the whole file.
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		if offset, err = file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	if size == 0 && resp.ContentLength >= 0 {
+		size = offset + resp.ContentLength
+	}
+	p := &progress{w: f.opts.Progress, done: offset, total: size}
+	if _, err := io.Copy(file, io.TeeReader(resp.Body, p)); err != nil {
+		return fmt.Errorf("download of bundle %v was interrupted; run the command again to resume it: %w", id, err)
+	}
+	p.print()
+	fmt.Fprintln(f.opts.Progress)
+	return finish(file, part, path)
+}
+
+func finish(file *os.File, part string, path string) error {
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(part, path)
+}
+
+// statusError is returned if the server responds with an unexpected status.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e statusError) Error() string {
+	if e.body == "" {
+		return fmt.Sprintf("unexpected response status %v", e.code)
+	}
+	return fmt.Sprintf("unexpected response status %v: %v", e.code, e.body)
+}
+
+// do sends the request to the bundle endpoint and returns the response if its
+// status is 2xx.
+func (f *Fetcher) do(ctx context.Context, method string, path string, body io.Reader,
+	header http.Header) (*http.Response, error) {
+	u := *f.base
+	u.Path += bundlesPath + path
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if f.opts.Token != "" {
+		req.Header.Set("Authorization", "token="+f.opts.Token)
+	}
+	resp, err := f.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		return nil, statusError{code: resp.StatusCode, body: strings.TrimSpace(string(data))}
+	}
+	return resp, nil
+}
+
+// newID returns a random bundle ID in the UUID format.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b)
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+// progress prints the downloaded size at most once a second.
+type progress struct {
+	w       io.Writer
+	done    int64
+	total   int64
+	printed time.Time
+}
+
+func (p *progress) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	if time.Since(p.printed) >= time.Second {
+		p.print()
+	}
+	return len(b), nil
+}
+
+func (p *progress) print() {
+	p.printed = time.Now()
+	if p.total > 0 {
+		fmt.Fprintf(p.w, "\rDownloaded %v of %v (%d%%)", formatSize(p.done), formatSize(p.total),
+			p.done*100/p.total)
+		return
+	}
+	fmt.Fprintf(p.w, "\rDownloaded %v", formatSize(p.done))
+}
+
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
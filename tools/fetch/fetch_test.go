@@ -0,0 +1,171 @@
+package fetch
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+const token = "secret-token"
+
+// diagnostics is a stand-in of the dcos-diagnostics bundle endpoints. A
+// bundle is done after the given number of status requests.
+type diagnostics struct {
+	mu       sync.Mutex
+	archive  []byte
+	polls    int
+	statuses map[string]int
+	ranges   []string
+	fail     bool
+}
+
+func (d *diagnostics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "token="+token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, bundlesPath)
+	id := strings.TrimSuffix(path, "/file")
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n, ok := d.statuses[id]
+	switch {
+	case r.Method == http.MethodPut && !ok:
+		d.statuses[id] = 0
+		_ = json.NewEncoder(w).Encode(BundleInfo{ID: id, Status: SStarted})
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodGet && path == id:
+		d.statuses[id]++
+		info := BundleInfo{ID: id, Status: SInProgress}
+		if n >= d.polls {
+			info.Status = SDone
+			info.Size = int64(len(d.archive))
+			if d.fail {
+				info.Status = SFailed
+				info.Errors = []string{"10.0.0.2: connection refused"}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(info)
+	case r.Method == http.MethodGet:
+		d.ranges = append(d.ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "bundle.zip", time.Time{}, bytes.NewReader(d.archive))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newDiagnostics(t *testing.T) *diagnostics {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	w, err := z.Create("bundle/10.0.0.1_master/dcos-diagnostics-health.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(`{"units": []}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &diagnostics{archive: buf.Bytes(), polls: 2, statuses: make(map[string]int)}
+}
+
+func newFetcher(t *testing.T, url string) *Fetcher {
+	f, err := New(Options{URL: url, Token: token, PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestFetch(t *testing.T) {
+	d := newDiagnostics(t)
+	ts := httptest.NewServer(d)
+	defer ts.Close()
+	dir := t.TempDir()
+	archive, err := newFetcher(t, ts.URL).Fetch(context.Background(), "", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, d.archive) {
+		t.Fatal("The downloaded archive differs from the served one")
+	}
+	extracted := filepath.Join(dir, "bundle")
+	if err := bundle.Extract(archive, extracted); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.OpenExtracted(extracted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Hosts) != 1 || b.Hosts[0].IP.String() != "10.0.0.1" {
+		t.Errorf("Expected the master 10.0.0.1, observed %v", b.Hosts)
+	}
+}
+
+func TestFetchResume(t *testing.T) {
+	d := newDiagnostics(t)
+	ts := httptest.NewServer(d)
+	defer ts.Close()
+	dir := t.TempDir()
+	id := "0ba1a4c3-6a0e-4e7a-9d2c-7a1c3b1f1f00"
+	d.statuses[id] = d.polls
+	archive := filepath.Join(dir, "bundle-"+id+".zip")
+	if err := ioutil.WriteFile(archive+".part", d.archive[:10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newFetcher(t, ts.URL).Fetch(context.Background(), id, dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, d.archive) {
+		t.Error("The resumed archive differs from the served one")
+	}
+	if len(d.ranges) != 1 || d.ranges[0] != "bytes=10-" {
+		t.Errorf("Expected one request with Range: bytes=10-, observed %q", d.ranges)
+	}
+}
+
+func TestFetchFailed(t *testing.T) {
+	d := newDiagnostics(t)
+	d.fail = true
+	ts := httptest.NewServer(d)
+	defer ts.Close()
+	_, err := newFetcher(t, ts.URL).Fetch(context.Background(), "", t.TempDir())
+	if !errors.Is(err, ErrBundleFailed) || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Expected ErrBundleFailed with the cluster errors, observed %v", err)
+	}
+}
+
+func TestFetchUnauthorized(t *testing.T) {
+	ts := httptest.NewServer(newDiagnostics(t))
+	defer ts.Close()
+	f, err := New(Options{URL: ts.URL, Token: "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Fetch(context.Background(), "", t.TempDir()); err == nil ||
+		!strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected the 401 status error, observed %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package files
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/cluster"
+)
+
+// Selector selects the hosts; if it's empty, the bundle root or all the hosts
+// where the file type can be found are selected.
+type Selector struct {
+	// Hosts are IP addresses, hostnames, or host types, e.g. public-agent.
+	Hosts []string
+	// Leader selects the Mesos leader.
+	Leader bool
+}
+
+// Select returns the bundle root and the hosts matching the selector. The
+// root is only selected if the selector is empty or has the "root" host.
+func (s Selector) Select(b bundle.Bundle) ([]bundle.Host, error) {
+	var selected []bundle.Host
+	var leader bundle.Host
+	if s.Leader {
+		m, err := cluster.New(b).MesosLeader()
+		if err != nil {
+			return nil, err
+		}
+		leader = m.Host
+		selected = append(selected, leader)
+	}
+	root := bundle.Host{Directory: b.Directory}
+	if !s.Leader && len(s.Hosts) == 0 {
+		return append([]bundle.Host{root}, b.Hosts...), nil
+	}
+	for _, selector := range s.Hosts {
+		if strings.EqualFold(selector, string(bundle.DTRoot)) {
+			selected = append([]bundle.Host{root}, selected...)
+			break
+		}
+	}
+	for _, h := range b.Hosts {
+		if s.Leader && h.IP == leader.IP && h.Type == leader.Type {
+			continue
+		}
+		for _, selector := range s.Hosts {
+			if h.Matches(selector) {
+				selected = append(selected, h)
+				break
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no hosts match %v", strings.Join(s.Hosts, ", "))
+	}
+	return selected, nil
+}
+
+// Cat writes the decompressed content of the files of the given type from
+// the selected hosts to w. If the file is found on several hosts, each file
+// is preceded by a header, e.g. "==> master 10.0.0.1 <==". With pretty set,
+// JSON files are indented. It returns an error wrapping
+// bundle.ErrFileNotFound if the file isn't found on any of the hosts.
+func Cat(b bundle.Bundle, typeName bundle.FileTypeName, s Selector, pretty bool, w io.Writer) error {
+	t, err := bundle.GetFileType(typeName)
+	if err != nil {
+		return err
+	}
+	if pretty && t.ContentType != bundle.CTJson {
+		return fmt.Errorf("cannot pretty print %v: it's not JSON but %v", typeName, t.ContentType)
+	}
+	hosts, err := s.Select(b)
+	if err != nil {
+		return err
+	}
+	var found []bundle.Host
+	for _, h := range hosts {
+		if !t.ExistsOn(h.Type) {
+			continue
+		}
+		if _, err := h.FilePaths(typeName); err == nil {
+			found = append(found, h)
+		} else if !errors.Is(err, bundle.ErrFileNotFound) {
+			return err
+		}
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("%w: %v on the selected hosts", bundle.ErrFileNotFound, typeName)
+	}
+	for i, h := range found {
+		if len(found) > 1 {
+			if i > 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "==> %v <==\n", hostLabel(h)); err != nil {
+				return err
+			}
+		}
+		if err := cat(h.Directory, typeName, pretty, w); err != nil {
+			return fmt.Errorf("%v: %w", hostLabel(h), err)
+		}
+	}
+	return nil
+}
+
+func cat(d bundle.Directory, typeName bundle.FileTypeName, pretty bool, w io.Writer) error {
+	f, err := d.OpenFile(typeName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if !pretty {
+		if _, err := io.Copy(w, f); err != nil {
+			return err
+		}
+		return bundle.Partial(f)
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return fmt.Errorf("cannot pretty print %v: %w", f.Name(), err)
+	}
+	buf.WriteByte('\n')
+	if _, err := buf.WriteTo(w); err != nil {
+		return err
+	}
+	return bundle.Partial(f)
+}
+
+// hostLabel returns the host type and the IP address, or "root" for the
+// bundle root directory.
+func hostLabel(h bundle.Host) string {
+	if h.IP.IsZero() {
+		return string(h.Type)
+	}
+	return fmt.Sprintf("%v %v", h.Type, h.IP)
+}
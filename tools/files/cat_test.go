@@ -0,0 +1,64 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+func TestCatLeader(t *testing.T) {
+	b, err := bundle.New("test_bundles/cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := Cat(b, "mesos-master-state", Selector{Leader: true}, true, &out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n  \"hostname\": \"10.0.0.1\",\n  \"slaves\": []\n}\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, observed %q", expected, out.String())
+	}
+}
+
+func TestCatSeveralHosts(t *testing.T) {
+	b, err := bundle.New("test_bundles/cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := Cat(b, "mesos-master-state", Selector{Hosts: []string{"master"}}, false, &out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "==> master 10.0.0.1 <==\n{\"hostname\":\"10.0.0.1\",\"slaves\":[]}\n" +
+		"==> master 10.0.0.2 <==\n{\"hostname\":\"10.0.0.1\",\"slaves\":[]}"
+	if out.String() != expected {
+		t.Errorf("Expected %q, observed %q", expected, out.String())
+	}
+	err = Cat(b, "mesos-agent-state", Selector{}, false, &out)
+	if !errors.Is(err, bundle.ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound, observed %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	b, err := bundle.New("test_bundles/cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := List(b, Selector{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 file types, observed %+v", entries)
+	}
+	if entries[0].FileType.Name != "mesos-agent-log" || len(entries[0].Hosts) != 1 {
+		t.Errorf("Expected mesos-agent-log on 1 host, observed %+v", entries[0])
+	}
+	if entries[1].FileType.Name != "mesos-master-state" || len(entries[1].Hosts) != 2 {
+		t.Errorf("Expected mesos-master-state on 2 hosts, observed %+v", entries[1])
+	}
+}
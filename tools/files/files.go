@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -12,19 +13,23 @@ import (
 	"github.com/mesosphere/bun/v2/bundle"
 )
 
-// FindFiles finds file types from the bundle directory.
-func FindFiles(p string) ([]bundle.FileType, error) {
-	info, err := os.Stat(p)
-	if err != nil {
-		return nil, err
-	}
-	if !info.IsDir() {
-		return nil, fmt.Errorf("%v is not a directory", p)
-	}
-	fileTypes, errors := readDir(p)
-	if len(errors) != 0 {
-		// TODO: concat and return all errors
-		return nil, fmt.Errorf("errors found")
+// FindFiles finds file types from the bundle directories.
+func FindFiles(paths ...string) ([]bundle.FileType, error) {
+	var fileTypes []bundle.FileType
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%v is not a directory", p)
+		}
+		types, errors := readDir(p)
+		if len(errors) != 0 {
+			// TODO: concat and return all errors
+			return nil, fmt.Errorf("errors found")
+		}
+		fileTypes = append(fileTypes, types...)
 	}
 	fileTypes = squash(fileTypes)
 	for i, t := range fileTypes {
@@ -98,7 +103,7 @@ func removeDuplicates(elements []string) []string {
 func squash(types []bundle.FileType) []bundle.FileType {
 	typesByPath := make(map[string]bundle.FileType)
 	for _, t := range types {
-		p := strings.TrimSuffix(t.Paths[0], ".gz")
+		p := bundle.TrimCompressedExt(t.Paths[0])
 		d := t.DirTypes[0]
 		existing, ok := typesByPath[p]
 		if !ok {
@@ -152,7 +157,7 @@ func readDir(p string) ([]bundle.FileType, []error) {
 		return fileTypes, errors
 	}
 
-	name := strings.TrimSuffix(info.Name(), ".gz")
+	name := bundle.TrimCompressedExt(info.Name())
 	f := bundle.FileType{}
 
 	// ContentType
@@ -177,7 +182,7 @@ func readDir(p string) ([]bundle.FileType, []error) {
 	}
 
 	// Paths
-	f.Paths = append(f.Paths, strings.TrimSuffix(trimBasePath(p), ".gz"))
+	f.Paths = append(f.Paths, bundle.TrimCompressedExt(trimBasePath(p)))
 
 	//DirTypes
 	f.DirTypes = append(f.DirTypes, pathToDirType(p))
@@ -185,32 +190,27 @@ func readDir(p string) ([]bundle.FileType, []error) {
 	return []bundle.FileType{f}, nil
 }
 
-var re = regexp.MustCompile(`((([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.){3}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5]))_(agent_public|agent|master)(.*)`)
+// splitHostPath splits the path into the host directory type and the path
+// relative to the host directory. Files which are not in a host directory
+// belong to the root directory.
+func splitHostPath(p string) (bundle.DirType, string) {
+	parts := strings.Split(filepath.ToSlash(p), "/")
+	for i, part := range parts {
+		if _, dirType, ok := bundle.ParseHostDir(part); ok {
+			return dirType, strings.Join(parts[i+1:], "/")
+		}
+	}
+	return bundle.DTRoot, path.Base(p)
+}
 
 // detect DirType by path
 func pathToDirType(p string) bundle.DirType {
-	groups := re.FindStringSubmatch(p)
-	if groups == nil {
-		return bundle.DTRoot
-	}
-	switch groups[5] {
-	case "master":
-		return bundle.DTMaster
-	case "agent":
-		return bundle.DTAgent
-	case "agent_public":
-		return bundle.DTPublicAgent
-	default:
-		panic(fmt.Sprintf("unknown directory type: %v", groups[5]))
-	}
+	dirType, _ := splitHostPath(p)
+	return dirType
 }
 
 // trim the path to the base directory of the DirType
 func trimBasePath(p string) string {
-	groups := re.FindStringSubmatch(p)
-	if groups == nil {
-		// root directory
-		return path.Base(p)
-	}
-	return strings.TrimPrefix(groups[6], "/")
+	_, localPath := splitHostPath(p)
+	return localPath
 }
@@ -0,0 +1,33 @@
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChooseName(t *testing.T) {
+	for p, name := range map[string]string{
+		"dcos-mesos-slave.service":                  "mesos-agent",
+		"5050-master_state.json":                    "mesos-master-state",
+		"8443-v2_apps.json":                         "marathon-apps",
+		"dcos-logrotate-agent.timer":                "logrotate-agent-timer",
+		"dmesg-0.output":                            "dmesg",
+		"opt/mesosphere/active.buildinfo.full.json": "active-buildinfo-full",
+	} {
+		if observed := chooseName(p); observed != name {
+			t.Errorf("chooseName(%q): expected %q, observed %q", p, name, observed)
+		}
+	}
+}
+
+func FuzzChooseName(f *testing.F) {
+	f.Add("dcos-mesos-slave.service")
+	f.Add("5050-master_state.json")
+	f.Add("opt/mesosphere/active.buildinfo.full.json")
+	f.Fuzz(func(t *testing.T, p string) {
+		name := chooseName(p)
+		if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") || strings.Contains(name, "--") {
+			t.Errorf("chooseName(%q) = %q", p, name)
+		}
+	})
+}
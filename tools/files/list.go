@@ -0,0 +1,43 @@
+package files
+
+import (
+	"errors"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+// Entry is a file type found in the bundle and the hosts which have it.
+type Entry struct {
+	FileType bundle.FileType
+	Hosts    []bundle.Host
+}
+
+// List returns the registered file types found on the selected hosts sorted
+// by the file type name.
+func List(b bundle.Bundle, s Selector) ([]Entry, error) {
+	hosts, err := s.Select(b)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, t := range bundle.FileTypes() {
+		entry := Entry{FileType: t}
+		for _, h := range hosts {
+			if !t.ExistsOn(h.Type) {
+				continue
+			}
+			_, err := h.FilePaths(t.Name)
+			if errors.Is(err, bundle.ErrFileNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			entry.Hosts = append(entry.Hosts, h)
+		}
+		if len(entry.Hosts) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
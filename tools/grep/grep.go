@@ -0,0 +1,250 @@
+// Package grep searches bundle files, including compressed ones, selected by
+// file type and host.
+package grep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+// Options define what and where to search.
+type Options struct {
+	// Pattern is a regular expression in the RE2 syntax, or a string if
+	// Fixed is true.
+	Pattern    string
+	Fixed      bool
+	IgnoreCase bool
+	// FileTypes are names or glob patterns of the file type names, e.g.
+	// mesos-*-log; all the file types are searched if empty.
+	FileTypes []string
+	// ContentTypes limit the file types to the given content types.
+	ContentTypes []bundle.ContentType
+	// Hosts are IP addresses, hostnames, or directory types, e.g. master or
+	// public-agent; all the hosts and the bundle root are searched if empty.
+	Hosts []string
+	// Before and After are the numbers of the context lines.
+	Before int
+	After  int
+	// Count makes Grep print only the number of the matching lines per file.
+	Count bool
+}
+
+// Result summarizes the search.
+type Result struct {
+	// Matches is the number of the matching lines.
+	Matches int
+	// Partial are the files which were read partially.
+	Partial []string
+	// Errors are the errors of reading files; the search continues after them.
+	Errors []error
+}
+
+// Grep searches the bundle and writes the matching lines to w in the
+// host:filetype:line:text format; context lines use dashes instead of colons
+// and the groups of lines are separated with "--", like in grep.
+func Grep(b *bundle.Bundle, o Options, w io.Writer) (Result, error) {
+	var result Result
+	re, err := compile(o)
+	if err != nil {
+		return result, err
+	}
+	types, err := selectFileTypes(o)
+	if err != nil {
+		return result, err
+	}
+	dirs, err := selectDirectories(b, o.Hosts)
+	if err != nil {
+		return result, err
+	}
+	p := printer{w: w, o: o}
+	for _, d := range dirs {
+		for _, t := range types {
+			if !t.ExistsOn(d.dir.Type) {
+				continue
+			}
+			n, err := p.grep(d, t.Name, re)
+			result.Matches += n
+			if errors.Is(err, bundle.ErrPartialFile) {
+				result.Partial = append(result.Partial, fmt.Sprintf("%v:%v", d.label, t.Name))
+				continue
+			}
+			if err != nil {
+				if errors.Is(err, bundle.ErrFileNotFound) {
+					continue
+				}
+				result.Errors = append(result.Errors, fmt.Errorf("%v:%v: %w", d.label, t.Name, err))
+			}
+		}
+	}
+	return result, p.err
+}
+
+func compile(o Options) (*regexp.Regexp, error) {
+	pattern := o.Pattern
+	if o.Fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if o.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// selectFileTypes returns the registered file types matching the options.
+func selectFileTypes(o Options) ([]bundle.FileType, error) {
+	for _, pattern := range o.FileTypes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid file type pattern %q: %w", pattern, err)
+		}
+	}
+	var selected []bundle.FileType
+	for _, t := range bundle.FileTypes() {
+		if matchFileType(t, o) {
+			selected = append(selected, t)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no file types match the given names and content types")
+	}
+	return selected, nil
+}
+
+func matchFileType(t bundle.FileType, o Options) bool {
+	if len(o.ContentTypes) > 0 {
+		found := false
+		for _, ct := range o.ContentTypes {
+			if strings.EqualFold(string(ct), string(t.ContentType)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(o.FileTypes) == 0 {
+		return true
+	}
+	for _, pattern := range o.FileTypes {
+		if ok, _ := path.Match(pattern, string(t.Name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type directory struct {
+	label string
+	dir   bundle.Directory
+}
+
+// selectDirectories returns the bundle root and the host directories
+// matching the host selectors.
+func selectDirectories(b *bundle.Bundle, hosts []string) ([]directory, error) {
+	var dirs []directory
+	if len(hosts) == 0 {
+		dirs = append(dirs, directory{string(bundle.DTRoot), b.Directory})
+	}
+	for _, selector := range hosts {
+		if strings.EqualFold(selector, string(bundle.DTRoot)) {
+			dirs = append(dirs, directory{string(bundle.DTRoot), b.Directory})
+			break
+		}
+	}
+	for _, h := range b.Hosts {
+		if len(hosts) == 0 || matchHost(h, hosts) {
+			dirs = append(dirs, directory{h.IP.String(), h.Directory})
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no hosts match %v", strings.Join(hosts, ", "))
+	}
+	return dirs, nil
+}
+
+func matchHost(h bundle.Host, selectors []string) bool {
+	for _, s := range selectors {
+		if h.Matches(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// printer writes the matching lines with their context.
+type printer struct {
+	w io.Writer
+	o Options
+	// printed is true if any lines have been printed, so the next group of
+	// lines should be separated.
+	printed bool
+	err     error
+}
+
+func (p *printer) grep(d directory, t bundle.FileTypeName, re *regexp.Regexp) (int, error) {
+	type line struct {
+		n    int
+		text string
+	}
+	var before []line
+	matches := 0
+	last := 0
+	after := 0
+	prefix := d.label + ":" + string(t)
+	contextPrefix := d.label + "-" + string(t)
+	file, err := d.dir.ScanLines(t, func(n int, text string) bool {
+		text = strings.TrimRight(text, "\r\n")
+		if !re.MatchString(text) {
+			if after > 0 {
+				p.printf("%v-%v-%v\n", contextPrefix, n, text)
+				last = n
+				after--
+			} else if p.o.Before > 0 {
+				before = append(before, line{n, text})
+				if len(before) > p.o.Before {
+					before = before[1:]
+				}
+			}
+			return p.err != nil
+		}
+		matches++
+		if p.o.Count {
+			return false
+		}
+		if p.printed && (p.o.Before > 0 || p.o.After > 0) {
+			first := n - len(before)
+			if last == 0 || first > last+1 {
+				p.printf("--\n")
+			}
+		}
+		for _, l := range before {
+			p.printf("%v-%v-%v\n", contextPrefix, l.n, l.text)
+		}
+		before = before[:0]
+		p.printf("%v:%v:%v\n", prefix, n, text)
+		p.printed = true
+		last = n
+		after = p.o.After
+		return p.err != nil
+	})
+	if err != nil {
+		return matches, err
+	}
+	if p.o.Count && matches > 0 {
+		p.printf("%v:%v\n", prefix, matches)
+	}
+	return matches, bundle.Partial(file)
+}
+
+func (p *printer) printf(format string, a ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, a...)
+}
@@ -0,0 +1,56 @@
+package grep
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+func grep(t *testing.T, o Options) (Result, string) {
+	b, err := bundle.New("test_bundles/logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	result, err := Grep(&b, o, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, out.String()
+}
+
+func TestGrep(t *testing.T) {
+	result, out := grep(t, Options{Pattern: "OOM", IgnoreCase: true, FileTypes: []string{"mesos-*-log"}})
+	expected := "10.0.0.1:mesos-master-log:3:OOM killed 1\n" +
+		"10.0.0.1:mesos-master-log:7:OOM killed 2\n" +
+		"10.0.0.2:mesos-agent-log:2:oom killed 3\n"
+	if out != expected {
+		t.Errorf("Expected:\n%v\nobserved:\n%v", expected, out)
+	}
+	if result.Matches != 3 || len(result.Errors) != 0 {
+		t.Errorf("Expected 3 matches and no errors, observed %+v", result)
+	}
+}
+
+func TestGrepContext(t *testing.T) {
+	_, out := grep(t, Options{Pattern: "OOM", Hosts: []string{"master"}, Before: 1, After: 1})
+	expected := "10.0.0.1-mesos-master-log-2-line 2\n" +
+		"10.0.0.1:mesos-master-log:3:OOM killed 1\n" +
+		"10.0.0.1-mesos-master-log-4-line 4\n" +
+		"--\n" +
+		"10.0.0.1-mesos-master-log-6-line 6\n" +
+		"10.0.0.1:mesos-master-log:7:OOM killed 2\n" +
+		"10.0.0.1-mesos-master-log-8-line 8\n"
+	if out != expected {
+		t.Errorf("Expected:\n%v\nobserved:\n%v", expected, out)
+	}
+}
+
+func TestGrepCount(t *testing.T) {
+	_, out := grep(t, Options{Pattern: "10.0.0.3", Fixed: true, ContentTypes: []bundle.ContentType{bundle.CTOther},
+		Hosts: []string{"root"}, Count: true})
+	if expected := "root:summary-errors-report:1\n"; out != expected {
+		t.Errorf("Expected %q, observed %q", expected, out)
+	}
+}
@@ -2,14 +2,11 @@ package logstats
 
 import (
 	"bufio"
-	"compress/gzip"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"os"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -22,53 +19,43 @@ func LogStats(b *bundle.Bundle, w io.Writer) error {
 	if err != nil {
 		return fmt.Errorf("cannot write CSV. Cause: %s", err.Error())
 	}
-	var serviceRegexp = regexp.MustCompile(`.*(\.service$|\.service.gz)`)
 	for _, host := range b.Hosts {
-		files, err := ioutil.ReadDir(host.Path)
-		if err != nil {
-			return fmt.Errorf("cannot read dir. Cause: %s", err.Error())
-		}
-		for _, f := range files {
-			err := func() error {
-				if f.IsDir() || !serviceRegexp.MatchString(f.Name()) {
-					return nil
-				}
-				path := filepath.Join(host.Path, f.Name())
-				fileName := f.Name()
-				compressed := false
-				if filepath.Ext(fileName) == ".gz" {
-					compressed = true
-					fileName = strings.TrimSuffix(fileName, ".gz")
-				}
-				fileName = strings.TrimSuffix(fileName, ".service")
-				var fileReader io.ReadCloser
-				var gzReader io.ReadCloser
-				var reader io.ReadCloser
-				if fileReader, err = os.Open(path); err != nil {
-					return fmt.Errorf("cannot open file %s. Cause: %s", path, err.Error())
-				}
-				defer func() { _ = fileReader.Close() }()
-				reader = fileReader
-				if compressed {
-					if gzReader, err = gzip.NewReader(fileReader); err != nil {
+		// A host merged from several bundles may have several directories;
+		// a service log is taken from the first one which has it.
+		seen := make(map[string]bool)
+		for _, dir := range host.Paths() {
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				return fmt.Errorf("cannot read dir. Cause: %s", err.Error())
+			}
+			for _, f := range files {
+				err := func() error {
+					fileName := bundle.TrimCompressedExt(f.Name())
+					if f.IsDir() || filepath.Ext(fileName) != ".service" || seen[fileName] {
+						return nil
+					}
+					seen[fileName] = true
+					fileName = strings.TrimSuffix(fileName, ".service")
+					path := filepath.Join(dir, f.Name())
+					reader, err := bundle.Open(path)
+					if err != nil {
 						return fmt.Errorf("cannot open file %s. Cause: %s", path, err.Error())
 					}
-					defer func() { _ = gzReader.Close() }()
-					reader = gzReader
-				}
-				scanner := bufio.NewScanner(reader)
-				var lineCount int
-				for scanner.Scan() {
-					lineCount++
-				}
-				err := csvWriter.Write([]string{string(host.IP), string(host.Type), fileName, strconv.Itoa(lineCount)})
+					defer func() { _ = reader.Close() }()
+					scanner := bufio.NewScanner(reader)
+					var lineCount int
+					for scanner.Scan() {
+						lineCount++
+					}
+					err = csvWriter.Write([]string{host.IP.String(), string(host.Type), fileName, strconv.Itoa(lineCount)})
+					if err != nil {
+						return fmt.Errorf("cannot write CSV. Cause: %s", err.Error())
+					}
+					return nil
+				}()
 				if err != nil {
-					return fmt.Errorf("cannot write CSV. Cause: %s", err.Error())
+					return err
 				}
-				return nil
-			}()
-			if err != nil {
-				return err
 			}
 		}
 	}
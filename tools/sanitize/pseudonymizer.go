@@ -0,0 +1,214 @@
+package sanitize
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+var (
+	ipv4Regexp = regexp.MustCompile(`(?:[0-9]{1,3}\.){3}[0-9]{1,3}`)
+	ipv6Regexp = regexp.MustCompile(`[0-9A-Fa-f]*:[0-9A-Fa-f:]*:[0-9A-Fa-f:]*`)
+	// ipv4Pseudonyms is the benchmarking network (RFC 2544), so pseudonyms
+	// don't clash with real addresses.
+	ipv4Pseudonyms = net.IPv4(198, 18, 0, 0).To4()
+	// ipv6Pseudonyms is the documentation prefix (RFC 3849).
+	ipv6Pseudonyms = net.ParseIP("2001:db8::")
+)
+
+// hostnameSuffix is the domain of the hostname pseudonyms; .invalid is
+// reserved by RFC 2606.
+const hostnameSuffix = ".sanitized.invalid"
+
+// pseudonymizer consistently replaces IP addresses and hostnames with
+// pseudonyms.
+type pseudonymizer struct {
+	// ips and hostnames map the originals to the pseudonyms.
+	ips       map[string]string
+	hostnames map[string]string
+	nextIPv4  uint32
+	nextIPv6  uint32
+	// hostnameRegexp matches the hostnames case-insensitively; longer
+	// hostnames go first, so a domain doesn't clobber its subdomains. It's
+	// nil if there are no hostnames to replace.
+	hostnameRegexp *regexp.Regexp
+}
+
+// newPseudonymizer creates a pseudonymizer for the given hostnames and their
+// short forms, e.g. ip-10-0-0-2 for ip-10-0-0-2.ec2.internal. Only fully
+// qualified names and names with dashes are replaced, so common words are
+// left intact. IP addresses are discovered on the fly.
+func newPseudonymizer(hostnames []string) *pseudonymizer {
+	p := &pseudonymizer{
+		ips:       make(map[string]string),
+		hostnames: make(map[string]string),
+	}
+	var names []string
+	add := func(h string) {
+		if _, ok := p.hostnames[h]; ok || !strings.ContainsAny(h, ".-") || net.ParseIP(h) != nil {
+			return
+		}
+		p.hostnames[h] = ""
+		names = append(names, h)
+	}
+	for _, h := range hostnames {
+		add(strings.ToLower(h))
+	}
+	for _, h := range names {
+		if i := strings.IndexByte(h, '.'); i > 0 {
+			add(h[:i])
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	quoted := make([]string, 0, len(names))
+	for i, h := range names {
+		p.hostnames[h] = fmt.Sprintf("host-%v%v", i+1, hostnameSuffix)
+		quoted = append(quoted, regexp.QuoteMeta(h))
+	}
+	if len(quoted) > 0 {
+		p.hostnameRegexp = regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+	}
+	return p
+}
+
+// replace replaces the hostnames and the IP addresses in the string.
+func (p *pseudonymizer) replace(s string) string {
+	if p.hostnameRegexp != nil {
+		s = p.replaceHostnames(s)
+	}
+	s = replaceAddresses(ipv4Regexp, s, p.ipv4)
+	s = replaceAddresses(ipv6Regexp, s, p.ipv6)
+	return s
+}
+
+// ip returns the pseudonym of the host identity.
+func (p *pseudonymizer) ip(ip bundle.IP) bundle.IP {
+	var result bundle.IP
+	if ip.Address != "" {
+		result = bundle.ParseIP(p.replace(ip.Address))
+	}
+	if ip.Hostname != "" {
+		result.Hostname = bundle.ParseIP(p.replace(ip.Hostname)).Hostname
+		if result.Family == "" {
+			result.Family = bundle.AFHostname
+		}
+	}
+	return result
+}
+
+// replaceHostnames replaces the hostnames which are not parts of longer
+// alphanumeric words.
+func (p *pseudonymizer) replaceHostnames(s string) string {
+	matches := p.hostnameRegexp.FindAllStringIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m[0] > 0 && isAlphanumeric(s[m[0]-1]) || m[1] < len(s) && isAlphanumeric(s[m[1]]) {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(p.hostnames[strings.ToLower(s[m[0]:m[1]])])
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// ipv4 returns the pseudonym of the IPv4 address or false if the string is
+// not an address which should be replaced.
+func (p *pseudonymizer) ipv4(s string) (string, bool) {
+	ip := net.ParseIP(s).To4()
+	if ip == nil || keep(ip) || ip[0] == 255 {
+		return "", false
+	}
+	if pseudonym, ok := p.ips[ip.String()]; ok {
+		return pseudonym, true
+	}
+	p.nextIPv4++
+	n := binary.BigEndian.Uint32(ipv4Pseudonyms) + p.nextIPv4
+	pseudonym := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(pseudonym, n)
+	p.ips[ip.String()] = pseudonym.String()
+	return pseudonym.String(), true
+}
+
+// ipv6 works like ipv4 for IPv6 addresses.
+func (p *pseudonymizer) ipv6(s string) (string, bool) {
+	ip := net.ParseIP(s)
+	if ip == nil || ip.To4() != nil || keep(ip) {
+		return "", false
+	}
+	if pseudonym, ok := p.ips[ip.String()]; ok {
+		return pseudonym, true
+	}
+	p.nextIPv6++
+	pseudonym := make(net.IP, net.IPv6len)
+	copy(pseudonym, ipv6Pseudonyms)
+	binary.BigEndian.PutUint32(pseudonym[12:], p.nextIPv6)
+	p.ips[ip.String()] = pseudonym.String()
+	return pseudonym.String(), true
+}
+
+// keep returns true for the addresses which don't identify hosts.
+func keep(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()
+}
+
+// replaceAddresses replaces the regexp matches which are whole words, i.e.
+// are not parts of version numbers, hashes, etc. Underscores separate words,
+// e.g. in 10.0.0.1_master.
+func replaceAddresses(re *regexp.Regexp, s string, f func(string) (string, bool)) string {
+	matches := re.FindAllStringIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m[0] > 0 && (isAlphanumeric(s[m[0]-1]) || s[m[0]-1] == '.') || m[1] < len(s) && isAlphanumeric(s[m[1]]) {
+			continue
+		}
+		if m[1]+1 < len(s) && s[m[1]] == '.' && isAlphanumeric(s[m[1]+1]) {
+			continue
+		}
+		pseudonym, ok := f(s[m[0]:m[1]])
+		if !ok {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(pseudonym)
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// key returns the mapping of the pseudonyms to the originals.
+func (p *pseudonymizer) key() Key {
+	k := Key{IPs: make(map[string]string), Hostnames: make(map[string]string)}
+	for original, pseudonym := range p.ips {
+		k.IPs[pseudonym] = original
+	}
+	for original, pseudonym := range p.hostnames {
+		k.Hostnames[pseudonym] = original
+	}
+	return k
+}
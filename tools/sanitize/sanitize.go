@@ -0,0 +1,215 @@
+// Package sanitize writes a copy of a bundle with the secrets masked and
+// the IP addresses and hostnames replaced with pseudonyms.
+package sanitize
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/secrets"
+)
+
+// Key maps the pseudonyms to the original IP addresses and hostnames, so
+// the findings in a sanitized bundle can be decoded.
+type Key struct {
+	IPs       map[string]string `json:"ips"`
+	Hostnames map[string]string `json:"hostnames"`
+}
+
+// Report describes the sanitized bundle.
+type Report struct {
+	// Files is the number of the written files.
+	Files int
+	// Secrets is the number of the masked secrets of each kind.
+	Secrets map[secrets.Kind]int
+	// Partial are the files which were read partially.
+	Partial []string
+}
+
+// Sanitize writes a redacted copy of the bundle to the out directory which
+// should either not exist or be empty. Compressed files are written
+// decompressed. If several bundles are merged, the file from the first bundle
+// wins.
+func Sanitize(b *bundle.Bundle, out string) (Key, Report, error) {
+	report := Report{Secrets: make(map[secrets.Kind]int)}
+	out, err := filepath.Abs(out)
+	if err != nil {
+		return Key{}, report, err
+	}
+	if err := checkOut(b, out); err != nil {
+		return Key{}, report, err
+	}
+	p := newPseudonymizer(collectHostnames(b))
+	s := sanitizer{p: p, out: out, report: &report, hostDirs: make(map[string]bool)}
+	for _, host := range b.Hosts {
+		for _, path := range host.Paths() {
+			s.hostDirs[path] = true
+		}
+	}
+	for _, root := range b.Paths() {
+		if err := s.copyDir(root, out, true); err != nil {
+			return p.key(), report, err
+		}
+	}
+	for _, host := range b.Hosts {
+		dir := filepath.Join(out, bundle.HostDirName(p.ip(host.IP), host.Type))
+		for _, path := range host.Paths() {
+			if err := s.copyDir(path, dir, false); err != nil {
+				return p.key(), report, err
+			}
+		}
+	}
+	return p.key(), report, nil
+}
+
+// checkOut returns an error if the out directory is not empty or is inside
+// the bundle.
+func checkOut(b *bundle.Bundle, out string) error {
+	for _, root := range b.Paths() {
+		if rel, err := filepath.Rel(root, out); err == nil && !strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("output directory %v is inside the bundle %v", out, root)
+		}
+	}
+	infos, err := ioutil.ReadDir(out)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(infos) > 0 {
+		return fmt.Errorf("output directory %v is not empty", out)
+	}
+	return nil
+}
+
+// collectHostnames returns the hostnames of the cluster nodes known from the
+// host directory names and the Mesos state.
+func collectHostnames(b *bundle.Bundle) []string {
+	var hostnames []string
+	for _, host := range b.Hosts {
+		if host.IP.Hostname != "" {
+			hostnames = append(hostnames, host.IP.Hostname)
+		}
+	}
+	var state struct {
+		Hostname string `json:"hostname"`
+		Slaves   []struct {
+			Hostname string `json:"hostname"`
+		} `json:"slaves"`
+	}
+	read := func(typeName bundle.FileTypeName) {
+		_ = b.ForEachDirectory(typeName, func(d bundle.Directory) bool {
+			state.Hostname = ""
+			state.Slaves = nil
+			// The hostnames of a partially read state are redacted as well.
+			if err := d.ReadJSON(typeName, &state); err != nil && !errors.Is(err, bundle.ErrPartialFile) {
+				return false
+			}
+			hostnames = append(hostnames, state.Hostname)
+			for _, s := range state.Slaves {
+				hostnames = append(hostnames, s.Hostname)
+			}
+			return false
+		})
+	}
+	read("mesos-master-state")
+	read("mesos-master-agents")
+	read("mesos-agent-state")
+	return hostnames
+}
+
+type sanitizer struct {
+	p      *pseudonymizer
+	out    string
+	report *Report
+	// hostDirs are the paths of the host directories.
+	hostDirs map[string]bool
+}
+
+// copyDir copies the files from the src directory to the dst one. If root is
+// true, the host directories are skipped.
+func (s sanitizer) copyDir(src string, dst string, root bool) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			_, _, isHostDir := bundle.ParseHostDir(info.Name())
+			if root && path != src && (isHostDir || s.hostDirs[path]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, s.p.replace(bundle.TrimCompressedExt(rel)))
+		if _, err := os.Stat(target); err == nil {
+			// The file was taken from a bundle merged earlier.
+			return nil
+		}
+		return s.copyFile(path, target)
+	})
+}
+
+func (s sanitizer) copyFile(src string, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := bundle.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	r := bufio.NewReader(in)
+	var redactor secrets.Redactor
+	for {
+		line, readErr := r.ReadString('\n')
+		if line != "" {
+			line, findings := redactor.Redact(line)
+			for _, f := range findings {
+				s.report.Secrets[f.Kind]++
+			}
+			if _, err := w.WriteString(s.p.replace(line)); err != nil {
+				_ = out.Close()
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			if bundle.Partial(in) == nil {
+				_ = out.Close()
+				return fmt.Errorf("cannot read %v: %w", src, readErr)
+			}
+			break
+		}
+	}
+	if bundle.Partial(in) != nil {
+		s.report.Partial = append(s.report.Partial, src)
+	}
+	if err := w.Flush(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	s.report.Files++
+	return out.Close()
+}
@@ -0,0 +1,102 @@
+package sanitize
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/secrets"
+)
+
+func TestSanitize(t *testing.T) {
+	b, err := bundle.New("test_bundles/cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "sanitized")
+	key, report, err := Sanitize(&b, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Files != 4 {
+		t.Errorf("Expected 4 files, observed %v", report.Files)
+	}
+	if report.Secrets[secrets.KField] != 2 {
+		t.Errorf("Expected 2 secret fields, observed %v", report.Secrets)
+	}
+	sanitized, err := bundle.New(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sanitized.Hosts) != 2 {
+		t.Fatalf("Expected 2 hosts, observed %+v", sanitized.Hosts)
+	}
+	originals := make(map[string]bool)
+	for _, h := range sanitized.Hosts {
+		original := key.IPs[h.IP.String()] + key.Hostnames[h.IP.String()]
+		originals[original] = true
+	}
+	if !originals["10.0.0.1"] || !originals["ip-10-0-0-2.ec2.internal"] {
+		t.Errorf("Expected the key to decode the hosts, observed %v", originals)
+	}
+	var state struct {
+		Slaves []struct {
+			Hostname string `json:"hostname"`
+			PID      string `json:"pid"`
+		} `json:"slaves"`
+	}
+	if err := sanitized.Masters()[0].ReadJSON("mesos-master-state", &state); err != nil {
+		t.Fatal(err)
+	}
+	if h := state.Slaves[0].Hostname; key.Hostnames[h] != "ip-10-0-0-2.ec2.internal" {
+		t.Errorf("Expected pseudonymized agent hostname, observed %v", h)
+	}
+	if pid := state.Slaves[0].PID; pid != "slave(1)@198.18.0.3:5051" {
+		t.Errorf("Expected pseudonymized agent PID, observed %v", pid)
+	}
+	var all strings.Builder
+	_ = filepath.Walk(out, func(path string, _ os.FileInfo, _ error) error {
+		content, _ := ioutil.ReadFile(path)
+		all.Write(content)
+		return nil
+	})
+	for _, s := range []string{"10.0.0.", "ip-10-0-0-2", "hunter2", "$6$rounds"} {
+		if strings.Contains(all.String(), s) {
+			t.Errorf("Expected %q to be removed", s)
+		}
+	}
+}
+
+func TestSanitizeNotEmptyOut(t *testing.T) {
+	b, err := bundle.New("test_bundles/cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(out, "file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Sanitize(&b, out); err == nil {
+		t.Error("Expected an error for a non-empty output directory")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	p := newPseudonymizer([]string{"ip-10-0-0-2.ec2.internal", "localhost"})
+	for s, expected := range map[string]string{
+		"10.0.0.1:5050 and 10.0.0.1.":        "198.18.0.1:5050 and 198.18.0.1.",
+		"version 1.10.0.0.1 127.0.0.1":       "version 1.10.0.0.1 127.0.0.1",
+		"[fd01::1]:5051 at 12:00:00":         "[2001:db8::1]:5051 at 12:00:00",
+		"ip-10-0-0-2.ec2.internal 255.0.0.0": "host-1.sanitized.invalid 255.0.0.0",
+		"/var/lib/10.0.0.1_master/state":     "/var/lib/198.18.0.1_master/state",
+		"IP-10-0-0-2.EC2.Internal:5051":      "host-1.sanitized.invalid:5051",
+		"hostname ip-10-0-0-2, ip-10-0-0-20": "hostname host-2.sanitized.invalid, ip-10-0-0-20",
+	} {
+		if observed := p.replace(s); observed != expected {
+			t.Errorf("replace(%q): expected %q, observed %q", s, expected, observed)
+		}
+	}
+}
@@ -25,7 +25,7 @@ func ToCSV(b *bundle.Bundle, writer io.Writer) error {
 		agentsMap[a.Id] = a.Hostname
 	}
 	var err error
-	b.ForEachFile("mesos-master-frameworks",
+	e := b.ForEachFile("mesos-master-frameworks",
 		func(f bundle.File) (stop bool) {
 			defer func() { _ = f.Close() }()
 			decoder := json.NewDecoder(f)
@@ -56,6 +56,9 @@ func ToCSV(b *bundle.Bundle, writer io.Writer) error {
 			return true
 		},
 	)
+	if e != nil {
+		return e
+	}
 	return err
 }
 
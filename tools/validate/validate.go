@@ -0,0 +1,259 @@
+// Package validate checks if a diagnostics bundle is complete and its files
+// are intact.
+package validate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+)
+
+// Kind is a kind of a file problem.
+type Kind string
+
+const (
+	// KMissing means that none of the file type paths exists in the directory.
+	KMissing Kind = "missing"
+	// KEmpty means that the file has no content.
+	KEmpty = "empty"
+	// KUnreadable means that the file cannot be opened or read.
+	KUnreadable = "unreadable"
+	// KCorrupt means that the .gz file cannot be decompressed.
+	KCorrupt = "corrupt"
+)
+
+// Issue is a problem with a bundle file.
+type Issue struct {
+	// IP is empty for the files in the bundle root directory.
+	IP       bundle.IP
+	DirType  bundle.DirType
+	FileType bundle.FileTypeName
+	Kind     Kind
+	// Path is empty for the missing files.
+	Path  string
+	Error string
+}
+
+// MissingHost is an agent registered in Mesos which has no directory in the bundle.
+type MissingHost struct {
+	ID       string
+	IP       bundle.IP
+	Hostname string
+}
+
+// Report is the outcome of the bundle validation.
+type Report struct {
+	Issues       []Issue
+	MissingHosts []MissingHost
+	// AgentsChecked is false if the list of the registered agents couldn't be
+	// read, so the missing hosts are unknown.
+	AgentsChecked bool
+	// SummaryErrors are lines of the summary-errors-report file which is
+	// written by the bundle creator when it cannot collect something.
+	SummaryErrors []string
+}
+
+// Count returns the number of the issues of the given kind.
+func (r Report) Count(kind Kind) int {
+	var n int
+	for _, i := range r.Issues {
+		if i.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// SummaryErrorsFor returns the lines of the summary-errors-report which
+// mention the host. The address should not be a part of a longer one, e.g.
+// 10.0.0.1 doesn't match 10.0.0.10; it may be followed by a port unless it's
+// an IPv6 address.
+func (r Report) SummaryErrorsFor(ip bundle.IP) []string {
+	after := `[^0-9A-Za-z.-]`
+	if ip.Family == bundle.AFIPv6 {
+		after = `[^0-9A-Za-z.:-]`
+	}
+	address := regexp.MustCompile(`(^|[^0-9A-Za-z.:-])` + regexp.QuoteMeta(ip.String()) +
+		`\.?($|` + after + `)`)
+	var lines []string
+	for _, line := range r.SummaryErrors {
+		if address.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Validate walks the registered file types of the bundle flavor in every
+// bundle directory where they can be found and reports the missing, empty,
+// unreadable, and corrupted files. It also reports the agents which are
+// registered in Mesos but absent in the bundle.
+func Validate(b *bundle.Bundle) (Report, error) {
+	var report Report
+	// The root directory is represented as a host without an IP.
+	dirs := append([]bundle.Host{{Directory: b.Directory}}, b.Hosts...)
+	for _, t := range bundle.FileTypes() {
+		if t.Flavor != b.Flavor {
+			continue
+		}
+		for _, d := range dirs {
+			if !t.ExistsOn(d.Type) {
+				continue
+			}
+			for _, issue := range validateFile(d.Directory, t) {
+				issue.IP = d.IP
+				report.Issues = append(report.Issues, issue)
+			}
+		}
+	}
+	var err error
+	if report.SummaryErrors, err = readSummaryErrors(b); err != nil {
+		return report, err
+	}
+	report.MissingHosts, report.AgentsChecked = missingHosts(b)
+	return report, nil
+}
+
+// validateFile finds the files of the given type in the directory the same
+// way bundle.Directory.OpenFile does and checks them.
+func validateFile(d bundle.Directory, t bundle.FileType) []Issue {
+	issue := Issue{DirType: d.Type, FileType: t.Name}
+	paths, err := d.FilePaths(t.Name)
+	if errors.Is(err, bundle.ErrFileNotFound) {
+		issue.Kind = KMissing
+		return []Issue{issue}
+	}
+	if err != nil {
+		issue.Kind, issue.Error = KUnreadable, err.Error()
+		return []Issue{issue}
+	}
+	var issues []Issue
+	for _, filePath := range paths {
+		issue.Path = filePath
+		info, err := os.Stat(filePath)
+		if err != nil {
+			issue.Kind, issue.Error = KUnreadable, err.Error()
+			issues = append(issues, issue)
+			continue
+		}
+		if info.Size() == 0 {
+			issue.Kind = KEmpty
+			issues = append(issues, issue)
+			continue
+		}
+		if kind, err := readFile(filePath); err != nil {
+			issue.Kind, issue.Error = kind, err.Error()
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// readFile reads the file to the end; compressed files are decompressed, so
+// the truncated or otherwise broken archives are detected.
+func readFile(filePath string) (Kind, error) {
+	compressed := bundle.TrimCompressedExt(filePath) != filePath
+	f, err := bundle.Open(filePath)
+	if err != nil {
+		if compressed && !os.IsPermission(err) {
+			return KCorrupt, err
+		}
+		return KUnreadable, err
+	}
+	defer func() { _ = f.Close() }()
+	n, err := io.Copy(ioutil.Discard, f)
+	if err != nil {
+		if compressed {
+			return KCorrupt, err
+		}
+		return KUnreadable, err
+	}
+	if err := bundle.Partial(f); err != nil {
+		return KCorrupt, err
+	}
+	if n == 0 {
+		return KEmpty, fmt.Errorf("the archive is empty")
+	}
+	return "", nil
+}
+
+func readSummaryErrors(b *bundle.Bundle) ([]string, error) {
+	f, err := b.OpenFile("summary-errors-report")
+	if err != nil {
+		return nil, nil // the file is optional and is validated as any other file
+	}
+	defer func() { _ = f.Close() }()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return lines, fmt.Errorf("cannot read %v: %v", f.Name(), err)
+	}
+	return lines, nil
+}
+
+type agents struct {
+	Slaves []struct {
+		ID       string `json:"id"`
+		Hostname string `json:"hostname"`
+		PID      string `json:"pid"`
+	} `json:"slaves"`
+}
+
+// missingHosts returns the agents from the Mesos master agents list which
+// don't have a directory in the bundle. It returns false if the list cannot
+// be read, e.g. the bundle has no masters.
+func missingHosts(b *bundle.Bundle) ([]MissingHost, bool) {
+	if found, err := b.HasFiles("mesos-master-agents"); err != nil || !found {
+		return nil, false
+	}
+	var a agents
+	if err := b.ReadAnyJSON("mesos-master-agents", &a); err != nil {
+		return nil, false
+	}
+	present := make(map[bundle.IP]bool, len(b.Hosts))
+	for _, h := range b.Hosts {
+		present[h.IP] = true
+	}
+	var missing []MissingHost
+	for _, s := range a.Slaves {
+		hostname := bundle.ParseIP(s.Hostname)
+		ip := bundle.ParseIP(pidIP(s.PID))
+		if ip.IsZero() {
+			ip = hostname
+		}
+		if present[ip] || present[hostname] {
+			continue
+		}
+		missing = append(missing, MissingHost{ID: s.ID, IP: ip, Hostname: s.Hostname})
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].IP.String() < missing[j].IP.String()
+	})
+	return missing, true
+}
+
+// pidIP extracts the IP address from the Mesos agent PID, e.g. slave(1)@10.0.0.1:5051.
+func pidIP(pid string) string {
+	i := strings.LastIndex(pid, "@")
+	if i < 0 {
+		return ""
+	}
+	addr := pid[i+1:]
+	if j := strings.LastIndex(addr, ":"); j >= 0 {
+		addr = addr[:j]
+	}
+	return addr
+}
@@ -0,0 +1,123 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/bundle/bundletest"
+)
+
+func TestValidate(t *testing.T) {
+	b, err := bundle.New("test_bundles/partial")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Validate(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := make(map[bundle.FileTypeName]Issue)
+	for _, i := range report.Issues {
+		if i.Kind != KMissing {
+			issues[i.FileType] = i
+		}
+	}
+	if i := issues["mesos-master-state"]; i.Kind != KEmpty || i.IP.String() != "10.0.0.1" {
+		t.Errorf("Expected empty mesos-master-state on 10.0.0.1, observed %+v", i)
+	}
+	if i := issues["mesos-agent-state"]; i.Kind != KCorrupt || i.IP.String() != "10.0.0.2" {
+		t.Errorf("Expected corrupted mesos-agent-state on 10.0.0.2, observed %+v", i)
+	}
+	if len(issues) != 2 {
+		t.Errorf("Expected 2 issues other than missing files, observed %v", len(issues))
+	}
+	if report.Count(KMissing) == 0 {
+		t.Error("Expected missing files")
+	}
+	if !report.AgentsChecked {
+		t.Fatal("Expected agents to be checked")
+	}
+	if len(report.MissingHosts) != 1 || report.MissingHosts[0].IP.String() != "10.0.0.3" {
+		t.Fatalf("Expected missing host 10.0.0.3, observed %+v", report.MissingHosts)
+	}
+	if lines := report.SummaryErrorsFor(bundle.ParseIP("10.0.0.3")); len(lines) != 1 {
+		t.Errorf("Expected 1 summary error for 10.0.0.3, observed %v", lines)
+	}
+}
+
+func TestValidateCleanBundles(t *testing.T) {
+	dcos := bundletest.New(t)
+	kubernetes := bundletest.NewKubernetes(t)
+	for flavor, builder := range map[bundle.Flavor]*bundletest.Builder{bundle.FDCOS: dcos, bundle.FKubernetes: kubernetes} {
+		hosts := []*bundletest.HostBuilder{builder.Root()}
+		if flavor == bundle.FDCOS {
+			hosts = append(hosts, dcos.Master("10.0.0.1"), dcos.Agent("10.0.0.2"), dcos.PublicAgent("10.0.0.3"))
+		} else {
+			hosts = append(hosts, kubernetes.ControlPlane("cp-1", "10.0.0.1"), kubernetes.Worker("worker-1", "10.0.0.2"))
+		}
+		for _, ft := range bundle.FileTypes() {
+			if ft.Flavor != flavor {
+				continue
+			}
+			for _, h := range hosts {
+				if ft.ExistsOn(h.Host().Type) {
+					h.File(ft.Name, "content\n")
+				}
+			}
+		}
+		b := builder.Build()
+		report, err := Validate(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range report.Issues {
+			t.Errorf("%v: unexpected issue %+v", flavor, i)
+		}
+	}
+}
+
+func TestValidateWithoutMasters(t *testing.T) {
+	dcos := bundletest.New(t)
+	dcos.Agent("10.0.0.2").File("mesos-agent-state", `{"id": "a1"}`)
+	kubernetes := bundletest.NewKubernetes(t)
+	kubernetes.Worker("worker-1", "10.0.0.2").File("kubelet", "content\n")
+	for _, builder := range []*bundletest.Builder{dcos, kubernetes} {
+		b := builder.Build()
+		report, err := Validate(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.AgentsChecked {
+			t.Errorf("%v: expected the agents not to be checked without the Mesos masters", b.Flavor)
+		}
+	}
+}
+
+func TestSummaryErrorsFor(t *testing.T) {
+	report := Report{SummaryErrors: []string{
+		"10.0.0.1: cannot collect dcos-diagnostics",
+		"http://10.0.0.10:1050/system/health/v1/logs: timeout",
+		"host 10.0.0.100 is unreachable",
+		"ip-10-0-0-1.ec2.internal: timeout",
+		"cannot reach fd01::1:5",
+		"cannot reach fd01::1.",
+	}}
+	for ip, expected := range map[string]int{"10.0.0.1": 0, "10.0.0.10": 1, "fd01::1": 5} {
+		lines := report.SummaryErrorsFor(bundle.ParseIP(ip))
+		if len(lines) != 1 || lines[0] != report.SummaryErrors[expected] {
+			t.Errorf("Expected only the line about %v, observed %q", ip, lines)
+		}
+	}
+}
+
+func TestPidIP(t *testing.T) {
+	for pid, expected := range map[string]string{
+		"slave(1)@10.0.0.1:5051": "10.0.0.1",
+		"slave(1)@10.0.0.1":      "10.0.0.1",
+		"10.0.0.1":               "",
+	} {
+		if observed := pidIP(pid); observed != expected {
+			t.Errorf("pidIP(%q): expected %q, observed %q", pid, expected, observed)
+		}
+	}
+}
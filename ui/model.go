@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+)
+
+// maxLines limits the number of the lines loaded to the viewer.
+const maxLines = 200000
+
+// hostFiles is a bundle directory and the file types found in it.
+type hostFiles struct {
+	host      bundle.Host
+	fileTypes []bundle.FileTypeName
+}
+
+// label returns the host type and the IP address, or "root" for the bundle
+// root directory.
+func (h hostFiles) label() string {
+	return hostLabel(h.host)
+}
+
+func hostLabel(h bundle.Host) string {
+	if h.IP.IsZero() {
+		return string(h.Type)
+	}
+	return fmt.Sprintf("%v %v", h.Type, h.IP)
+}
+
+// listFiles returns the bundle root and the hosts with the file types found
+// in them. The root goes first, then the hosts in the bundle order.
+func listFiles(b bundle.Bundle) ([]hostFiles, error) {
+	dirs := make([]hostFiles, 0, len(b.Hosts)+1)
+	index := make(map[string]int)
+	dirs = append(dirs, hostFiles{host: bundle.Host{Directory: b.Directory}})
+	index[b.Path] = 0
+	for _, h := range b.Hosts {
+		index[h.Path] = len(dirs)
+		dirs = append(dirs, hostFiles{host: h})
+	}
+	for _, t := range bundle.FileTypes() {
+		name := t.Name
+		err := b.ForEachDirectory(name, func(d bundle.Directory) bool {
+			if _, err := d.FilePaths(name); err == nil {
+				i := index[d.Path]
+				dirs[i].fileTypes = append(dirs[i].fileTypes, name)
+			}
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+// readFile reads up to maxLines lines of the file decompressing it if needed.
+// It appends a note if the file was truncated or read partially.
+func readFile(d bundle.Directory, t bundle.FileTypeName) (string, error) {
+	f, err := d.OpenFile(t)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	var b strings.Builder
+	r := bufio.NewReader(f)
+	for n := 0; ; n++ {
+		if n == maxLines {
+			fmt.Fprintf(&b, "\n[Only the first %v lines are shown; use bun tool cat to see the whole file.]\n", maxLines)
+			return b.String(), nil
+		}
+		line, err := r.ReadString('\n')
+		b.WriteString(line)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return b.String(), err
+		}
+	}
+	if err := bundle.Partial(f); err != nil {
+		fmt.Fprintf(&b, "\n[%v]\n", err)
+	}
+	return b.String(), nil
+}
+
+// evidence returns the first evidence file type of the result which exists
+// on the result host. If the result has no such evidence, it falls back to the
+// files the check inspects; see checks.Check.Files.
+func evidence(r checks.Result, c checks.Check) (bundle.FileTypeName, bool) {
+	for _, names := range [][]bundle.FileTypeName{r.Evidence, c.Files()} {
+		for _, name := range names {
+			if _, err := r.Host.FilePaths(name); err == nil {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findLines returns the numbers of the lines, starting from 0, which contain
+// the query ignoring case.
+func findLines(text string, query string) []int {
+	if query == "" {
+		return nil
+	}
+	query = strings.ToLower(query)
+	var lines []int
+	for i, line := range strings.Split(text, "\n") {
+		if strings.Contains(strings.ToLower(line), query) {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
+
+// statusTag returns a colored status marker in the tview markup.
+func statusTag(s checks.Status) string {
+	switch s {
+	case checks.SProblem:
+		return "[red::b]P[-::-]"
+	case checks.SOK:
+		return "[green::b]✓[-::-]"
+	case checks.SSkipped:
+		return "[teal::b]S[-::-]"
+	default:
+		return "[yellow::b]U[-::-]"
+	}
+}
@@ -0,0 +1,295 @@
+// Package ui implements an interactive terminal UI for exploring a bundle:
+// check results, the files of each host, and a file viewer with search.
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+	"github.com/mesosphere/bun/v2/runner"
+)
+
+const help = "[::b]Tab[::-] next pane  [::b]Enter[::-] select  [::b]/[::-] search  " +
+	"[::b]n[::-]/[::b]N[::-] next/previous match  [::b]q[::-] quit"
+
+// fileRef is a reference of a file type node in the file browser.
+type fileRef struct {
+	host     bundle.Host
+	fileType bundle.FileTypeName
+}
+
+type app struct {
+	tv     *tview.Application
+	report runner.Report
+
+	checkList *tview.List
+	results   *tview.Table
+	files     *tview.TreeView
+	viewer    *tview.TextView
+	search    *tview.InputField
+	footer    *tview.TextView
+	panes     []tview.Primitive
+
+	// the selected check and its results
+	currentCheck checks.Check
+	current      checks.Results
+	// fileNodes are the file browser nodes by the host label and file type.
+	fileNodes map[string]*tview.TreeNode
+	hostNodes map[string]*tview.TreeNode
+	// text is the content of the viewer without markup.
+	text    string
+	matches []int
+	match   int
+}
+
+// Run shows the UI for the bundle and the check report until the user quits.
+func Run(b bundle.Bundle, report runner.Report) error {
+	a := &app{
+		tv:        tview.NewApplication(),
+		report:    report,
+		fileNodes: make(map[string]*tview.TreeNode),
+		hostNodes: make(map[string]*tview.TreeNode),
+	}
+	dirs, err := listFiles(b)
+	if err != nil {
+		return err
+	}
+	a.layout(dirs)
+	return a.tv.Run()
+}
+
+func (a *app) layout(dirs []hostFiles) {
+	a.checkList = tview.NewList().ShowSecondaryText(false)
+	a.checkList.SetBorder(true).SetTitle(" Checks ")
+	for _, c := range a.report.Checks {
+		a.checkList.AddItem(statusTag(c.Status())+" "+tview.Escape(c.Check.Name), "", 0, nil)
+	}
+	a.checkList.SetChangedFunc(func(i int, _ string, _ string, _ rune) {
+		a.showResults(i)
+	})
+	a.checkList.SetSelectedFunc(func(int, string, string, rune) {
+		a.tv.SetFocus(a.results)
+	})
+
+	a.results = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	a.results.SetBorder(true).SetTitle(" Results ")
+	a.results.SetSelectedFunc(func(row, _ int) {
+		if row > 0 && row <= len(a.current) {
+			a.jump(a.current[row-1])
+		}
+	})
+
+	a.files = tview.NewTreeView()
+	a.files.SetBorder(true).SetTitle(" Files ")
+	a.files.SetRoot(a.fileTree(dirs))
+	a.files.SetSelectedFunc(func(node *tview.TreeNode) {
+		if ref, ok := node.GetReference().(fileRef); ok {
+			a.open(ref.host, ref.fileType)
+			return
+		}
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	a.viewer = tview.NewTextView().SetRegions(true).SetWrap(false)
+	a.viewer.SetBorder(true).SetTitle(" Viewer ")
+
+	a.search = tview.NewInputField().SetLabel("Search: ")
+	a.search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			a.find(a.search.GetText())
+		}
+		a.tv.SetFocus(a.viewer)
+	})
+
+	a.footer = tview.NewTextView().SetDynamicColors(true).SetText(help)
+
+	left := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.checkList, 0, 1, true).
+		AddItem(a.files, 0, 1, false)
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.results, 0, 1, false).
+		AddItem(a.viewer, 0, 2, false).
+		AddItem(a.search, 1, 0, false)
+	main := tview.NewFlex().
+		AddItem(left, 0, 1, true).
+		AddItem(right, 0, 2, false)
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(main, 0, 1, true).
+		AddItem(a.footer, 1, 0, false)
+
+	a.panes = []tview.Primitive{a.checkList, a.results, a.files, a.viewer}
+	a.tv.SetInputCapture(a.handleKey)
+	a.tv.SetRoot(root, true).SetFocus(a.checkList)
+	if len(a.report.Checks) > 0 {
+		a.showResults(0)
+	}
+}
+
+func (a *app) fileTree(dirs []hostFiles) *tview.TreeNode {
+	root := tview.NewTreeNode("bundle").SetSelectable(false)
+	for _, d := range dirs {
+		if len(d.fileTypes) == 0 {
+			continue
+		}
+		label := d.label()
+		hostNode := tview.NewTreeNode(label).SetColor(tcell.ColorTeal).SetExpanded(d.host.IP.IsZero())
+		a.hostNodes[label] = hostNode
+		for _, t := range d.fileTypes {
+			node := tview.NewTreeNode(string(t)).SetReference(fileRef{d.host, t})
+			a.fileNodes[label+"/"+string(t)] = node
+			hostNode.AddChild(node)
+		}
+		root.AddChild(hostNode)
+	}
+	return root
+}
+
+func (a *app) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if a.tv.GetFocus() == a.search {
+		return event
+	}
+	switch {
+	case event.Key() == tcell.KeyTab:
+		a.cycleFocus(1)
+		return nil
+	case event.Key() == tcell.KeyBacktab:
+		a.cycleFocus(-1)
+		return nil
+	case event.Rune() == 'q':
+		a.tv.Stop()
+		return nil
+	case event.Rune() == '/':
+		a.tv.SetFocus(a.search)
+		return nil
+	case event.Rune() == 'n':
+		a.highlight(a.match + 1)
+		return nil
+	case event.Rune() == 'N':
+		a.highlight(a.match - 1)
+		return nil
+	}
+	return event
+}
+
+func (a *app) cycleFocus(step int) {
+	focused := a.tv.GetFocus()
+	for i, p := range a.panes {
+		if p == focused {
+			a.tv.SetFocus(a.panes[(i+step+len(a.panes))%len(a.panes)])
+			return
+		}
+	}
+	a.tv.SetFocus(a.panes[0])
+}
+
+func (a *app) showResults(i int) {
+	c := a.report.Checks[i]
+	a.currentCheck = c.Check
+	a.current = c.Results
+	a.results.Clear()
+	for col, title := range []string{"Status", "Host", "Details"} {
+		a.results.SetCell(0, col, tview.NewTableCell(title).SetSelectable(false).SetAttributes(tcell.AttrBold))
+	}
+	for row, r := range c.Results {
+		host := ""
+		if r.IsHostSet() {
+			host = hostLabel(r.Host)
+		}
+		if r.Partial {
+			host += " (partial data)"
+		}
+		value := strings.ReplaceAll(fmt.Sprintf("%v", r.Value), "\n", "; ")
+		if r.Value == nil {
+			value = ""
+		}
+		a.results.SetCell(row+1, 0, tview.NewTableCell(statusTag(r.Status)))
+		a.results.SetCell(row+1, 1, tview.NewTableCell(tview.Escape(host)))
+		a.results.SetCell(row+1, 2, tview.NewTableCell(tview.Escape(value)).SetExpansion(1))
+	}
+	a.results.SetTitle(fmt.Sprintf(" Results: %v ", tview.Escape(c.Check.Name)))
+	a.results.Select(1, 0).ScrollToBeginning()
+}
+
+// jump shows the evidence file of the result or, if there is no evidence,
+// the files of the result host. Results without evidence point to the files
+// the check inspects.
+func (a *app) jump(r checks.Result) {
+	if !r.IsHostSet() {
+		a.setStatus("The result is not related to a host.")
+		return
+	}
+	label := hostLabel(r.Host)
+	if name, ok := evidence(r, a.currentCheck); ok {
+		if node, ok := a.fileNodes[label+"/"+string(name)]; ok {
+			a.hostNodes[label].SetExpanded(true)
+			a.files.SetCurrentNode(node)
+		}
+		a.open(r.Host, name)
+		return
+	}
+	node, ok := a.hostNodes[label]
+	if !ok {
+		a.setStatus(fmt.Sprintf("No files of %v in the bundle.", label))
+		return
+	}
+	node.SetExpanded(true)
+	a.files.SetCurrentNode(node)
+	a.tv.SetFocus(a.files)
+}
+
+func (a *app) open(h bundle.Host, t bundle.FileTypeName) {
+	text, err := readFile(h.Directory, t)
+	if err != nil {
+		a.setStatus(fmt.Sprintf("[red]Cannot read %v: %v[-]", t, tview.Escape(err.Error())))
+		if text == "" {
+			return
+		}
+	}
+	a.text = text
+	a.matches = nil
+	a.viewer.SetText(tview.Escape(text)).ScrollToBeginning()
+	a.viewer.SetTitle(fmt.Sprintf(" %v: %v ", tview.Escape(hostLabel(h)), t))
+	a.tv.SetFocus(a.viewer)
+	if query := a.search.GetText(); query != "" {
+		a.find(query)
+	}
+}
+
+// find marks the lines of the viewer text which contain the query and
+// scrolls to the first one.
+func (a *app) find(query string) {
+	a.matches = findLines(a.text, query)
+	if len(a.matches) == 0 {
+		a.viewer.SetText(tview.Escape(a.text))
+		a.setStatus(fmt.Sprintf("%v not found.", tview.Escape(strconv.Quote(query))))
+		return
+	}
+	lines := strings.Split(a.text, "\n")
+	for i := range lines {
+		lines[i] = tview.Escape(lines[i])
+	}
+	for i, n := range a.matches {
+		lines[n] = fmt.Sprintf(`["%v"]%v[""]`, i, lines[n])
+	}
+	a.viewer.SetText(strings.Join(lines, "\n"))
+	a.highlight(0)
+}
+
+func (a *app) highlight(i int) {
+	if len(a.matches) == 0 {
+		return
+	}
+	a.match = (i + len(a.matches)) % len(a.matches)
+	a.viewer.Highlight(fmt.Sprint(a.match)).ScrollToHighlight()
+	a.setStatus(fmt.Sprintf("Match %v of %v, line %v.", a.match+1, len(a.matches), a.matches[a.match]+1))
+}
+
+func (a *app) setStatus(s string) {
+	a.footer.SetText(s + "  " + help)
+}
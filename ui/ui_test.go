@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/mesosphere/bun/v2/bundle"
+	"github.com/mesosphere/bun/v2/checks"
+	"github.com/mesosphere/bun/v2/runner"
+)
+
+func TestListFiles(t *testing.T) {
+	b, err := bundle.New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirs, err := listFiles(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 2 || dirs[0].label() != "root" || dirs[1].label() != "master 10.0.0.1" {
+		t.Fatalf("Expected the root and the master, observed %+v", dirs)
+	}
+	if types := dirs[1].fileTypes; len(types) != 2 || types[0] != "dcos-version" || types[1] != "mesos-master-log" {
+		t.Errorf("Expected dcos-version and mesos-master-log, observed %v", types)
+	}
+}
+
+func TestJumpToEvidence(t *testing.T) {
+	b, err := bundle.New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := runner.RunBundle(context.Background(), b, runner.Options{Checks: []string{"dcos-version"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	a := &app{
+		tv:        tview.NewApplication().SetScreen(screen),
+		report:    report,
+		fileNodes: make(map[string]*tview.TreeNode),
+		hostNodes: make(map[string]*tview.TreeNode),
+	}
+	dirs, err := listFiles(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.layout(dirs)
+	a.jump(a.current[0])
+	if a.text != `{"version": "2.1.0"}` {
+		t.Errorf("Expected dcos-version to be shown, observed %q", a.text)
+	}
+	a.open(b.Hosts[0], "mesos-master-log")
+	a.find("oom")
+	if len(a.matches) != 1 || a.matches[0] != 1 {
+		t.Errorf("Expected a match on the line 1, observed %v", a.matches)
+	}
+	if text := a.viewer.GetText(true); !strings.Contains(text, "line 3 [red]") {
+		t.Errorf("Expected the markup to be escaped, observed %q", text)
+	}
+}
+
+func TestEvidenceMissing(t *testing.T) {
+	b, err := bundle.New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := checks.Result{Host: b.Hosts[0], Evidence: []bundle.FileTypeName{"mesos-master-state", "dcos-version"}}
+	if name, ok := evidence(r, checks.Check{}); !ok || name != "dcos-version" {
+		t.Errorf("Expected dcos-version, observed %v", name)
+	}
+}
+
+func TestEvidenceFallsBackToCheckFiles(t *testing.T) {
+	b, err := bundle.New("test_bundles/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checks.Check{
+		Requires: []bundle.FileTypeName{"mesos-master-state"},
+		Doc:      checks.Doc{Files: []bundle.FileTypeName{"mesos-master-log"}},
+	}
+	if name, ok := evidence(checks.Result{Host: b.Hosts[0]}, c); !ok || name != "mesos-master-log" {
+		t.Errorf("Expected mesos-master-log, observed %v", name)
+	}
+}